@@ -0,0 +1,410 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	contextType  = reflect.TypeOf((*context.Context)(nil)).Elem()
+	commandType  = reflect.TypeOf((*Command)(nil))
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+	setterType   = reflect.TypeOf((*interface{ Set(string) error })(nil)).Elem()
+)
+
+// checkActionSignature checks that fn is a function of the form
+// func(context.Context, *Command, args...) [error] whose argument
+// parameters can be converted from strings
+func checkActionSignature(fn interface{}) error {
+	fnType := reflect.TypeOf(fn)
+	if fnType.Kind() != reflect.Func {
+		return fmt.Errorf("action must be a function, got %s", fnType)
+	}
+
+	if fnType.NumIn() < 2 || !contextType.AssignableTo(fnType.In(0)) || !commandType.AssignableTo(fnType.In(1)) {
+		return fmt.Errorf("action must start with (context.Context, *cli.Command) parameters, got %s", fnType)
+	}
+
+	for i := 2; i < fnType.NumIn(); i++ {
+		paramType := fnType.In(i)
+		if fnType.IsVariadic() && i == fnType.NumIn()-1 {
+			paramType = paramType.Elem()
+		}
+		if !isConvertibleArgType(paramType) {
+			return fmt.Errorf("action parameter %d has unsupported type %s", i-2, paramType)
+		}
+	}
+
+	switch {
+	case fnType.NumOut() > 1:
+		return fmt.Errorf("action must return at most one value, got %d", fnType.NumOut())
+	case fnType.NumOut() == 1 && fnType.Out(0) != errorType:
+		return fmt.Errorf("action must return error, got %s", fnType.Out(0))
+	}
+
+	return nil
+}
+
+// isConvertibleArgType reports whether convertArgument can produce a value of type t
+func isConvertibleArgType(t reflect.Type) bool {
+	if t == readerType {
+		return true
+	}
+	if reflect.PointerTo(t).Implements(setterType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Ptr:
+		return isConvertibleArgType(t.Elem())
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+// checkActionArgs checks the action against the declared arguments: every
+// parameter needs a declared argument (when any are declared), a variadic
+// argument must be last and line up with a variadic parameter, and defaults
+// must convert to the parameter type
+func (c *Command) checkActionArgs() error {
+	for i, arg := range c.args {
+		if arg.Variadic && i != len(c.args)-1 {
+			return fmt.Errorf("variadic argument %q must be the last argument", arg.Name)
+		}
+	}
+
+	if c.action == nil || len(c.args) == 0 || len(c.argFields) > 0 {
+		return nil
+	}
+
+	actionType := c.invoker.fnType
+	numParams := actionType.NumIn() - 2
+	numFixed := numParams
+	if actionType.IsVariadic() {
+		numFixed--
+	}
+
+	if numFixed > len(c.args) {
+		return fmt.Errorf("action parameter %d has no declared argument (%d declared)", len(c.args), len(c.args))
+	}
+
+	for i, arg := range c.args {
+		paramType := c.actionParamType(i)
+		if paramType == nil {
+			if numParams > 0 && arg.Variadic {
+				return fmt.Errorf("variadic argument %q has no variadic action parameter", arg.Name)
+			}
+			continue
+		}
+		if i > numFixed && actionType.IsVariadic() {
+			return fmt.Errorf("argument %q follows the variadic parameter", arg.Name)
+		}
+		if i < numFixed && arg.Variadic {
+			return fmt.Errorf("variadic argument %q has no variadic action parameter", arg.Name)
+		}
+		if arg.Default != "" {
+			if _, err := convertArgument(arg.Default, paramType); err != nil {
+				return fmt.Errorf("default %q of argument %q: %v", arg.Default, arg.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkDefinition returns the first problem with this command's action and
+// argument definitions, or nil
+func (c *Command) checkDefinition() error {
+	if c.actionErr != nil {
+		return c.actionErr
+	}
+	if err := c.checkActionArgs(); err != nil {
+		return &DefinitionError{
+			Msg: err.Error(),
+			Cmd: c,
+		}
+	}
+	return nil
+}
+
+// Validate checks the action signatures and argument definitions of this
+// command and all its subcommands (building lazy ones), returning every
+// problem found. Invalid definitions are also reported when the command is
+// executed.
+func (c *Command) Validate() error {
+	var errs []error
+	if err := c.checkDefinition(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.checkGroup(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.checkDefaultCommand(); err != nil {
+		errs = append(errs, err)
+	}
+	for _, subcmd := range c.sortedSubcommands() {
+		if err := subcmd.materialize().Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// argConverter converts a positional argument string to a parameter value
+type argConverter func(arg string) (reflect.Value, error)
+
+// invoker is an action compiled once by Action: converters for each
+// parameter are resolved up front, and common signatures get a fast path
+// that calls the function without reflection
+type invoker struct {
+	fn         reflect.Value
+	fnType     reflect.Type
+	converters []argConverter  // One per fixed parameter
+	zeros      []reflect.Value // Zero value per fixed parameter, for omitted arguments
+	variadic   argConverter    // Element converter for the variadic parameter, nil if none
+	fast       func(ctx context.Context, c *Command, args []string) error
+}
+
+// compileInvoker checks the action signature and builds its invoker
+func compileInvoker(fn interface{}) (*invoker, error) {
+	if err := checkActionSignature(fn); err != nil {
+		return nil, err
+	}
+
+	fnType := reflect.TypeOf(fn)
+	inv := &invoker{
+		fn:     reflect.ValueOf(fn),
+		fnType: fnType,
+		fast:   fastInvoker(fn),
+	}
+
+	numFixed := fnType.NumIn() - 2
+	if fnType.IsVariadic() {
+		numFixed--
+		inv.variadic = converterFor(fnType.In(fnType.NumIn() - 1).Elem())
+	}
+	for i := 0; i < numFixed; i++ {
+		paramType := fnType.In(i + 2)
+		inv.converters = append(inv.converters, converterFor(paramType))
+		inv.zeros = append(inv.zeros, reflect.Zero(paramType))
+	}
+
+	return inv, nil
+}
+
+// fastInvoker returns a direct call for common signatures, or nil
+func fastInvoker(fn interface{}) func(ctx context.Context, c *Command, args []string) error {
+	switch f := fn.(type) {
+	case func(context.Context, *Command) error:
+		return func(ctx context.Context, c *Command, args []string) error {
+			return f(ctx, c)
+		}
+	case func(context.Context, *Command):
+		return func(ctx context.Context, c *Command, args []string) error {
+			f(ctx, c)
+			return nil
+		}
+	case func(context.Context, *Command, string) error:
+		return func(ctx context.Context, c *Command, args []string) error {
+			return f(ctx, c, stringArg(c, args, 0))
+		}
+	case func(context.Context, *Command, string, string) error:
+		return func(ctx context.Context, c *Command, args []string) error {
+			return f(ctx, c, stringArg(c, args, 0), stringArg(c, args, 1))
+		}
+	case func(context.Context, *Command, ...string) error:
+		return func(ctx context.Context, c *Command, args []string) error {
+			return f(ctx, c, args...)
+		}
+	case func(context.Context, *Command, string, ...string) error:
+		return func(ctx context.Context, c *Command, args []string) error {
+			if len(args) == 0 {
+				return f(ctx, c, stringArg(c, args, 0))
+			}
+			return f(ctx, c, args[0], args[1:]...)
+		}
+	}
+	return nil
+}
+
+// stringArg returns the value at position i, its default, or ""
+func stringArg(c *Command, args []string, i int) string {
+	value, _ := c.argValue(args, i)
+	return value
+}
+
+// prepare converts the arguments (with defaults already applied) to the
+// parameter values of the reflective call. The fast path needs no values.
+func (inv *invoker) prepare(c *Command, args []string) ([]reflect.Value, error) {
+	if inv.fast != nil {
+		return nil, nil
+	}
+
+	callArgs := make([]reflect.Value, 0, len(inv.converters)+1)
+	for i, convert := range inv.converters {
+		raw, ok := c.argValue(args, i)
+		if !ok {
+			// Use zero value for optional arguments (nil for pointer parameters)
+			callArgs = append(callArgs, inv.zeros[i])
+			continue
+		}
+
+		argValue, err := convert(raw)
+		if err != nil {
+			return nil, c.conversionError(i, err)
+		}
+		callArgs = append(callArgs, argValue)
+	}
+
+	if inv.variadic != nil {
+		numFixed := len(inv.converters)
+		variadicCount := 0
+		if len(args) > numFixed {
+			variadicCount = len(args) - numFixed
+		}
+
+		sliceType := inv.fnType.In(inv.fnType.NumIn() - 1)
+		variadicSlice := reflect.MakeSlice(sliceType, variadicCount, variadicCount)
+		for i := 0; i < variadicCount; i++ {
+			argValue, err := inv.variadic(args[numFixed+i])
+			if err != nil {
+				return nil, c.conversionError(numFixed+i, err)
+			}
+			variadicSlice.Index(i).Set(argValue)
+		}
+		callArgs = append(callArgs, variadicSlice)
+	}
+
+	return callArgs, nil
+}
+
+// call invokes the action, through the fast path when available
+func (inv *invoker) call(ctx context.Context, c *Command, args []string, callArgs []reflect.Value) error {
+	if inv.fast != nil {
+		return inv.fast(ctx, c, args)
+	}
+
+	in := make([]reflect.Value, 0, len(callArgs)+2)
+	in = append(in, reflect.ValueOf(ctx), reflect.ValueOf(c))
+	in = append(in, callArgs...)
+
+	var results []reflect.Value
+	if inv.variadic != nil {
+		// CallSlice expands the slice as the variadic parameter
+		results = inv.fn.CallSlice(in)
+	} else {
+		results = inv.fn.Call(in)
+	}
+
+	// Check if there's an error return value
+	if len(results) > 0 && !results[0].IsNil() {
+		return results[0].Interface().(error)
+	}
+	return nil
+}
+
+// converterFor returns a converter for the target type. Common kinds are
+// parsed directly; anything else goes through the flag value parser.
+func converterFor(targetType reflect.Type) argConverter {
+	if targetType == readerType {
+		return func(arg string) (reflect.Value, error) {
+			return reflect.ValueOf(&Input{Name: arg}), nil
+		}
+	}
+
+	if targetType.Kind() == reflect.Ptr {
+		elemType := targetType.Elem()
+		convertElem := converterFor(elemType)
+		return func(arg string) (reflect.Value, error) {
+			elemValue, err := convertElem(arg)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			ptr := reflect.New(elemType)
+			ptr.Elem().Set(elemValue)
+			return ptr, nil
+		}
+	}
+
+	// Types with their own Set method keep the flag parser semantics
+	if reflect.PointerTo(targetType).Implements(setterType) {
+		return func(arg string) (reflect.Value, error) {
+			return parseWithFlagSet(arg, targetType)
+		}
+	}
+
+	switch targetType.Kind() {
+	case reflect.String:
+		return func(arg string) (reflect.Value, error) {
+			v := reflect.New(targetType).Elem()
+			v.SetString(arg)
+			return v, nil
+		}
+	case reflect.Bool:
+		return func(arg string) (reflect.Value, error) {
+			val, err := strconv.ParseBool(arg)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v := reflect.New(targetType).Elem()
+			v.SetBool(val)
+			return v, nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if targetType == durationType {
+			return func(arg string) (reflect.Value, error) {
+				dur, err := time.ParseDuration(arg)
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				v := reflect.New(targetType).Elem()
+				v.SetInt(int64(dur))
+				return v, nil
+			}
+		}
+		return func(arg string) (reflect.Value, error) {
+			val, err := strconv.ParseInt(arg, 10, targetType.Bits())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v := reflect.New(targetType).Elem()
+			v.SetInt(val)
+			return v, nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(arg string) (reflect.Value, error) {
+			val, err := strconv.ParseUint(arg, 10, targetType.Bits())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v := reflect.New(targetType).Elem()
+			v.SetUint(val)
+			return v, nil
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(arg string) (reflect.Value, error) {
+			val, err := strconv.ParseFloat(arg, targetType.Bits())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v := reflect.New(targetType).Elem()
+			v.SetFloat(val)
+			return v, nil
+		}
+	}
+
+	return func(arg string) (reflect.Value, error) {
+		return parseWithFlagSet(arg, targetType)
+	}
+}
@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestActionSignatureValidation tests that invalid action signatures are reported instead of panicking
+func TestActionSignatureValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		action   interface{}
+		contains string
+	}{
+		{"not a function", "run", "must be a function"},
+		{"missing params", func() error { return nil }, "must start with"},
+		{"missing cmd param", func(ctx context.Context) error { return nil }, "must start with"},
+		{"wrong first param", func(s string, c *Command) error { return nil }, "must start with"},
+		{"unsupported param", func(ctx context.Context, c *Command, m map[string]int) error { return nil }, "unsupported type"},
+		{"unsupported variadic param", func(ctx context.Context, c *Command, v ...chan int) error { return nil }, "unsupported type"},
+		{"non-error return", func(ctx context.Context, c *Command) string { return "" }, "must return error"},
+		{"too many returns", func(ctx context.Context, c *Command) (int, error) { return 0, nil }, "at most one value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := Root("app").Action(tt.action)
+
+			err := cmd.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Fatalf("expected error containing %q, got %v", tt.contains, err)
+			}
+
+			// Executing reports the same error instead of panicking
+			err = cmd.ExecuteWithArgs([]string{})
+			var defErr *DefinitionError
+			if !errors.As(err, &defErr) {
+				t.Fatalf("expected DefinitionError, got %T", err)
+			}
+			if defErr.Cmd != cmd {
+				t.Error("command reference should be preserved")
+			}
+		})
+	}
+}
+
+// TestActionSignatureValid tests that supported signatures pass validation
+func TestActionSignatureValid(t *testing.T) {
+	actions := []interface{}{
+		func(ctx context.Context, c *Command) error { return nil },
+		func(ctx context.Context, c *Command) {},
+		func(ctx context.Context, c *Command, name string, count int, ratio *float64) error { return nil },
+		func(ctx context.Context, c *Command, files ...ExistingFile) error { return nil },
+		func(ctx context.Context, c *Command, in *Input) error { return nil },
+	}
+
+	for _, action := range actions {
+		if err := Root("app").Action(action).Validate(); err != nil {
+			t.Errorf("unexpected error for %T: %v", action, err)
+		}
+	}
+}
+
+// TestActionArgumentConsistency tests validation of the action against declared arguments
+func TestActionArgumentConsistency(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmd      *Command
+		contains string
+	}{
+		{
+			"parameter without argument",
+			Cmd("deploy").
+				Arg("env", "Environment", true).
+				Action(func(ctx context.Context, c *Command, env string, replicas int) error { return nil }),
+			"no declared argument",
+		},
+		{
+			"variadic argument not last",
+			Cmd("deploy").
+				ArgVariadic("files", "Files", false).
+				Arg("dest", "Destination", true),
+			"must be the last",
+		},
+		{
+			"variadic argument on fixed parameter",
+			Cmd("deploy").
+				ArgVariadic("files", "Files", false).
+				Action(func(ctx context.Context, c *Command, file string) error { return nil }),
+			"no variadic action parameter",
+		},
+		{
+			"argument after variadic parameter",
+			Cmd("deploy").
+				Arg("first", "First", true).
+				Arg("second", "Second", false).
+				Arg("third", "Third", false).
+				Action(func(ctx context.Context, c *Command, first string, rest ...string) error { return nil }),
+			"follows the variadic parameter",
+		},
+		{
+			"invalid default",
+			Cmd("deploy").
+				ArgDefault("replicas", "Replicas", "many").
+				Action(func(ctx context.Context, c *Command, replicas int) error { return nil }),
+			"default",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := Root("app")
+			root.AddCommand(tt.cmd)
+
+			err := root.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Fatalf("expected error containing %q, got %v", tt.contains, err)
+			}
+			if !strings.Contains(err.Error(), "app deploy") {
+				t.Errorf("error should name the command path, got %q", err.Error())
+			}
+		})
+	}
+}
+
+// TestInvokerFastPath tests that common signatures skip reflection and keep the same semantics
+func TestInvokerFastPath(t *testing.T) {
+	var got []string
+	tests := []struct {
+		name   string
+		action interface{}
+		args   []string
+		want   []string
+	}{
+		{"no args", func(ctx context.Context, c *Command) error { got = nil; return nil }, nil, nil},
+		{"one string", func(ctx context.Context, c *Command, a string) error { got = []string{a}; return nil }, []string{"x"}, []string{"x"}},
+		{"two strings", func(ctx context.Context, c *Command, a, b string) error { got = []string{a, b}; return nil }, []string{"x"}, []string{"x", ""}},
+		{"variadic", func(ctx context.Context, c *Command, rest ...string) error { got = rest; return nil }, []string{"x", "y"}, []string{"x", "y"}},
+		{"string and variadic", func(ctx context.Context, c *Command, a string, rest ...string) error {
+			got = append([]string{a}, rest...)
+			return nil
+		}, []string{"x", "y", "z"}, []string{"x", "y", "z"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := Root("app").ValidateArgs(ArbitraryArgs).Action(tt.action)
+			if cmd.invoker.fast == nil {
+				t.Fatal("expected a fast path")
+			}
+			if err := cmd.ExecuteWithArgs(tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("errors are returned", func(t *testing.T) {
+		expected := errors.New("boom")
+		cmd := Root("app").Action(func(ctx context.Context, c *Command) error { return expected })
+		if err := cmd.ExecuteWithArgs(nil); err != expected {
+			t.Errorf("expected %v, got %v", expected, err)
+		}
+	})
+
+	t.Run("defaults apply", func(t *testing.T) {
+		var env string
+		cmd := Root("app").
+			ArgDefault("env", "Environment", "dev").
+			Action(func(ctx context.Context, c *Command, e string) error { env = e; return nil })
+		if err := cmd.ExecuteWithArgs(nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if env != "dev" {
+			t.Errorf("expected default 'dev', got %q", env)
+		}
+	})
+}
+
+// TestConverterMatchesFlagParser tests that compiled converters agree with the flag value parser
+func TestConverterMatchesFlagParser(t *testing.T) {
+	tests := []struct {
+		value string
+		typ   interface{}
+	}{
+		{"hello", ""},
+		{"42", int(0)},
+		{"-8", int8(0)},
+		{"300", int8(0)},
+		{"7", uint16(0)},
+		{"-1", uint(0)},
+		{"3.5", float32(0)},
+		{"true", false},
+		{"nope", false},
+		{"1m30s", time.Duration(0)},
+		{"data/input.txt", ExistingFile("")},
+	}
+
+	for _, tt := range tests {
+		targetType := reflect.TypeOf(tt.typ)
+		compiled, compiledErr := convertArgument(tt.value, targetType)
+		parsed, parsedErr := parseWithFlagSet(tt.value, targetType)
+
+		if (compiledErr == nil) != (parsedErr == nil) {
+			t.Errorf("%s(%q): compiled error %v, parser error %v", targetType, tt.value, compiledErr, parsedErr)
+			continue
+		}
+		if compiledErr != nil {
+			if compiledErr.Error() != parsedErr.Error() {
+				t.Errorf("%s(%q): error %q, want %q", targetType, tt.value, compiledErr, parsedErr)
+			}
+			continue
+		}
+		if compiled.Interface() != parsed.Interface() {
+			t.Errorf("%s(%q): got %v, want %v", targetType, tt.value, compiled, parsed)
+		}
+	}
+}
+
+func BenchmarkConvertArgumentCompiled(b *testing.B) {
+	convert := converterFor(reflect.TypeOf(0))
+	for i := 0; i < b.N; i++ {
+		if _, err := convert("12345"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkConvertArgumentFlagParser(b *testing.B) {
+	intType := reflect.TypeOf(0)
+	for i := 0; i < b.N; i++ {
+		if _, err := parseWithFlagSet("12345", intType); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkInvokeFastPath(b *testing.B) {
+	cmd := Root("app").
+		Arg("name", "Name", true).
+		Action(func(ctx context.Context, c *Command, name string) error { return nil })
+	args := []string{"world"}
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		callArgs, err := cmd.invoker.prepare(cmd, args)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := cmd.invoker.call(ctx, cmd, args, callArgs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkInvokeReflect(b *testing.B) {
+	cmd := Root("app").
+		Arg("name", "Name", true).
+		Action(func(ctx context.Context, c *Command, name string) error { return nil })
+	cmd.invoker.fast = nil // force the reflective path
+	args := []string{"world"}
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		callArgs, err := cmd.invoker.prepare(cmd, args)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := cmd.invoker.call(ctx, cmd, args, callArgs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkExecuteTypedArgs(b *testing.B) {
+	cmd := Root("app").
+		Arg("count", "Count", true).
+		Arg("ratio", "Ratio", true).
+		Action(func(ctx context.Context, c *Command, count int, ratio float64) error { return nil })
+	args := []string{"42", "0.5"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := cmd.ExecuteWithArgs(args); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
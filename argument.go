@@ -1,8 +1,302 @@
 package cli
 
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
 // Argument represents a positional argument for a command
 type Argument struct {
-	Name        string
-	Description string
-	Required    bool
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+	Variadic    bool     `json:"variadic,omitempty"` // Consumes all remaining arguments (last argument only)
+	Default     string   `json:"default,omitempty"`  // Value used when the argument is omitted
+	Choices     []string `json:"choices,omitempty"`  // Allowed values (empty allows any value)
+	Type        string   `json:"type,omitempty"`     // Type hint: string, int, uint, float, bool, duration, file or dir
+}
+
+// argTypeHints maps Argument.Type hints to the types used for validation
+var argTypeHints = map[string]reflect.Type{
+	"string":   reflect.TypeOf(""),
+	"int":      reflect.TypeOf(int64(0)),
+	"uint":     reflect.TypeOf(uint64(0)),
+	"float":    reflect.TypeOf(float64(0)),
+	"bool":     reflect.TypeOf(false),
+	"duration": reflect.TypeOf(time.Duration(0)),
+}
+
+// usage returns the argument as shown in the usage line, e.g. "<files...>"
+func (a Argument) usage() string {
+	name := a.Name
+	if a.Variadic {
+		name += "..."
+	}
+	if a.Required {
+		return "<" + name + ">"
+	}
+	return "[" + name + "]"
+}
+
+// validate checks a single value against the argument's choices and type hint
+func (a Argument) validate(value string) error {
+	if len(a.Choices) > 0 {
+		valid := false
+		for _, choice := range a.Choices {
+			if value == choice {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("invalid value %q, must be one of: %s", value, strings.Join(a.Choices, ", "))
+		}
+	}
+	if targetType, ok := argTypeHints[a.Type]; ok {
+		if _, err := convertArgument(value, targetType); err != nil {
+			return fmt.Errorf("invalid %s value %q: %v", a.Type, value, err)
+		}
+	}
+	return nil
+}
+
+// ArgsValidator validates the positional arguments passed to a command.
+// Validators run after flag parsing and before any lifecycle hook.
+type ArgsValidator func(cmd *Command, args []string) error
+
+// NoArgs rejects any positional arguments. On a command with subcommands the
+// first argument is reported as an unknown command.
+func NoArgs(cmd *Command, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	if len(cmd.subcommands) > 0 {
+		return cmd.commandNotFound(args[0])
+	}
+	return &ArgumentError{
+		Msg: fmt.Sprintf("accepts no arguments, got %d", len(args)),
+		Cmd: cmd,
+	}
+}
+
+// ArbitraryArgs accepts any number of positional arguments
+func ArbitraryArgs(cmd *Command, args []string) error {
+	return nil
+}
+
+// ExactArgs requires exactly n positional arguments
+func ExactArgs(n int) ArgsValidator {
+	return func(cmd *Command, args []string) error {
+		if len(args) != n {
+			return &ArgumentError{
+				Msg: fmt.Sprintf("accepts exactly %d argument(s), got %d", n, len(args)),
+				Cmd: cmd,
+			}
+		}
+		return nil
+	}
+}
+
+// MinimumArgs requires at least n positional arguments
+func MinimumArgs(n int) ArgsValidator {
+	return func(cmd *Command, args []string) error {
+		if len(args) < n {
+			return &ArgumentError{
+				Msg: fmt.Sprintf("requires at least %d argument(s), got %d", n, len(args)),
+				Cmd: cmd,
+			}
+		}
+		return nil
+	}
+}
+
+// MaximumArgs accepts at most n positional arguments
+func MaximumArgs(n int) ArgsValidator {
+	return func(cmd *Command, args []string) error {
+		if len(args) > n {
+			return &ArgumentError{
+				Msg: fmt.Sprintf("accepts at most %d argument(s), got %d", n, len(args)),
+				Cmd: cmd,
+			}
+		}
+		return nil
+	}
+}
+
+// RangeArgs requires between min and max positional arguments (inclusive)
+func RangeArgs(min, max int) ArgsValidator {
+	return func(cmd *Command, args []string) error {
+		if len(args) < min || len(args) > max {
+			return &ArgumentError{
+				Msg: fmt.Sprintf("accepts between %d and %d argument(s), got %d", min, max, len(args)),
+				Cmd: cmd,
+			}
+		}
+		return nil
+	}
+}
+
+// argValue returns the value for position i, falling back to the argument's
+// default. ok is false if the argument was omitted and has no default.
+func (c *Command) argValue(args []string, i int) (string, bool) {
+	if i < len(args) {
+		return args[i], true
+	}
+	if i < len(c.args) && c.args[i].Default != "" {
+		return c.args[i].Default, true
+	}
+	return "", false
+}
+
+// argField is a struct field bound to positional arguments with Command.Args
+type argField struct {
+	index int           // Position of the argument (the first one for rest)
+	rest  bool          // Receives all remaining arguments
+	value reflect.Value // Addressable field value
+}
+
+// parseArgStruct derives argument metadata and field bindings from the
+// `arg` tags of a struct. Tag format: `arg:"0,name=src,required"` or
+// `arg:"rest"`, with optional `usage`, `default` and `choices:"a|b"` tags.
+func parseArgStruct(structPtr interface{}) ([]Argument, []argField) {
+	v := reflect.ValueOf(structPtr)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		panic("Args requires a pointer to a struct")
+	}
+
+	structValue := v.Elem()
+	structType := structValue.Type()
+
+	var fields []argField
+	var restField *argField
+	byIndex := make(map[int]Argument)
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldValue := structValue.Field(i)
+
+		tag := field.Tag.Get("arg")
+		if tag == "" || !fieldValue.CanSet() {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		arg := Argument{
+			Name:        kebabCase(field.Name),
+			Description: field.Tag.Get("usage"),
+			Default:     field.Tag.Get("default"),
+			Type:        pathTypeHint(field.Type),
+		}
+		if choices := field.Tag.Get("choices"); choices != "" {
+			arg.Choices = strings.Split(choices, "|")
+		}
+		for _, opt := range parts[1:] {
+			opt = strings.TrimSpace(opt)
+			switch {
+			case opt == "required":
+				arg.Required = true
+			case strings.HasPrefix(opt, "name="):
+				arg.Name = strings.TrimPrefix(opt, "name=")
+			default:
+				panic(fmt.Sprintf("unknown arg tag option %q on field %s", opt, field.Name))
+			}
+		}
+
+		position := strings.TrimSpace(parts[0])
+		if position == "rest" {
+			if field.Type.Kind() != reflect.Slice {
+				panic(fmt.Sprintf("arg field %s tagged rest must be a slice", field.Name))
+			}
+			if restField != nil {
+				panic(fmt.Sprintf("arg field %s: only one field can be tagged rest", field.Name))
+			}
+			arg.Variadic = true
+			restField = &argField{rest: true, value: fieldValue}
+			byIndex[-1] = arg
+			continue
+		}
+
+		index, err := strconv.Atoi(position)
+		if err != nil || index < 0 {
+			panic(fmt.Sprintf("invalid arg position %q on field %s", position, field.Name))
+		}
+		if _, exists := byIndex[index]; exists {
+			panic(fmt.Sprintf("arg field %s: position %d is already bound", field.Name, index))
+		}
+		byIndex[index] = arg
+		fields = append(fields, argField{index: index, value: fieldValue})
+	}
+
+	// Positions must be contiguous from 0, followed by the rest field
+	var args []Argument
+	for i := 0; i < len(fields); i++ {
+		arg, ok := byIndex[i]
+		if !ok {
+			panic(fmt.Sprintf("arg positions must be contiguous, missing position %d", i))
+		}
+		args = append(args, arg)
+	}
+	if restField != nil {
+		restField.index = len(args)
+		args = append(args, byIndex[-1])
+		fields = append(fields, *restField)
+	}
+
+	return args, fields
+}
+
+// kebabCase converts a Go identifier to kebab-case, e.g. "SourceFile" -> "source-file"
+func kebabCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Start a new word at a lower-to-upper boundary or at the end of an acronym
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// bindArgFields converts the resolved positional arguments into the fields
+// bound with Args. Omitted arguments reset their field to the zero value.
+func (c *Command) bindArgFields(args []string) error {
+	for _, field := range c.argFields {
+		if field.rest {
+			count := 0
+			if len(args) > field.index {
+				count = len(args) - field.index
+			}
+			slice := reflect.MakeSlice(field.value.Type(), count, count)
+			for i := 0; i < count; i++ {
+				elem, err := convertArgument(args[field.index+i], field.value.Type().Elem())
+				if err != nil {
+					return c.conversionError(field.index+i, err)
+				}
+				slice.Index(i).Set(elem)
+			}
+			field.value.Set(slice)
+			continue
+		}
+
+		raw, ok := c.argValue(args, field.index)
+		if !ok {
+			field.value.Set(reflect.Zero(field.value.Type()))
+			continue
+		}
+		value, err := convertArgument(raw, field.value.Type())
+		if err != nil {
+			return c.conversionError(field.index, err)
+		}
+		field.value.Set(value)
+	}
+	return nil
 }
@@ -0,0 +1,440 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestArgsValidators tests the built-in argument count validators
+func TestArgsValidators(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator ArgsValidator
+		args      []string
+		wantErr   bool
+	}{
+		{"NoArgs with none", NoArgs, []string{}, false},
+		{"NoArgs with one", NoArgs, []string{"a"}, true},
+		{"ArbitraryArgs", ArbitraryArgs, []string{"a", "b", "c"}, false},
+		{"ExactArgs match", ExactArgs(2), []string{"a", "b"}, false},
+		{"ExactArgs too few", ExactArgs(2), []string{"a"}, true},
+		{"ExactArgs too many", ExactArgs(2), []string{"a", "b", "c"}, true},
+		{"MinimumArgs satisfied", MinimumArgs(1), []string{"a", "b"}, false},
+		{"MinimumArgs too few", MinimumArgs(1), []string{}, true},
+		{"MaximumArgs satisfied", MaximumArgs(2), []string{"a"}, false},
+		{"MaximumArgs too many", MaximumArgs(2), []string{"a", "b", "c"}, true},
+		{"RangeArgs inside", RangeArgs(1, 3), []string{"a", "b"}, false},
+		{"RangeArgs below", RangeArgs(1, 3), []string{}, true},
+		{"RangeArgs above", RangeArgs(1, 3), []string{"a", "b", "c", "d"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := Root("test").
+				ValidateArgs(tt.validator).
+				Action(func(ctx context.Context, c *Command) error {
+					return nil
+				})
+
+			err := cmd.ExecuteWithArgs(tt.args)
+			if tt.wantErr {
+				argErr, ok := err.(*ArgumentError)
+				if !ok {
+					t.Fatalf("expected ArgumentError, got %T (%v)", err, err)
+				}
+				if argErr.Usage == "" {
+					t.Error("expected usage hint on ArgumentError")
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestArgsValidatorRunsBeforeHooks tests that validation fails before any hook runs
+func TestArgsValidatorRunsBeforeHooks(t *testing.T) {
+	hookRan := false
+
+	root := Root("app").
+		PersistentPreRun(func(ctx context.Context, c *Command) error {
+			hookRan = true
+			return nil
+		})
+	deploy := Cmd("deploy").
+		ValidateArgs(ExactArgs(1)).
+		PreRun(func(ctx context.Context, c *Command) error {
+			hookRan = true
+			return nil
+		}).
+		Action(func(ctx context.Context, c *Command) error {
+			return nil
+		})
+	root.AddCommand(deploy)
+
+	err := root.ExecuteWithArgs([]string{"deploy"})
+	if _, ok := err.(*ArgumentError); !ok {
+		t.Fatalf("expected ArgumentError, got %T", err)
+	}
+	if hookRan {
+		t.Error("hooks should not run when argument validation fails")
+	}
+}
+
+// TestArgsValidatorCustom tests custom validators and error wrapping
+func TestArgsValidatorCustom(t *testing.T) {
+	onlyLower := func(cmd *Command, args []string) error {
+		for _, arg := range args {
+			if strings.ToLower(arg) != arg {
+				return errors.New("arguments must be lowercase")
+			}
+		}
+		return nil
+	}
+
+	cmd := Root("test").
+		ValidateArgs(MinimumArgs(1), onlyLower).
+		Action(func(ctx context.Context, c *Command, names ...string) error {
+			return nil
+		})
+
+	if err := cmd.ExecuteWithArgs([]string{"abc"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := cmd.ExecuteWithArgs([]string{"abc", "DEF"})
+	argErr, ok := err.(*ArgumentError)
+	if !ok {
+		t.Fatalf("expected ArgumentError, got %T", err)
+	}
+	if argErr.Msg != "arguments must be lowercase" {
+		t.Errorf("unexpected message %q", argErr.Msg)
+	}
+	if argErr.Cmd != cmd {
+		t.Error("command reference should be set")
+	}
+	if !strings.Contains(argErr.Error(), "usage: test") {
+		t.Errorf("error should include usage hint, got %q", argErr.Error())
+	}
+}
+
+// TestUsageIgnoresHiddenSubcommands tests that hidden subcommands do not add
+// a [command] marker to the usage
+func TestUsageIgnoresHiddenSubcommands(t *testing.T) {
+	root := Root("app")
+	deploy := Cmd("deploy").
+		Arg("environment", "Target environment", true).
+		Action(func(ctx context.Context, c *Command, env string) error {
+			return nil
+		})
+	deploy.AddCommand(Cmd("__internal").Hidden())
+	root.AddCommand(deploy)
+
+	err := root.ExecuteWithArgs([]string{"deploy"})
+	if err == nil || !strings.Contains(err.Error(), "usage: app deploy <environment>") {
+		t.Errorf("expected usage without [command], got %v", err)
+	}
+
+	output := captureStdout(t, deploy.showHelp)
+	if strings.Contains(output, "[command]") {
+		t.Errorf("help should not show [command], got:\n%s", output)
+	}
+}
+
+// TestArgsWithoutAction tests that a command without an action accepts args
+func TestArgsWithoutAction(t *testing.T) {
+	var seen []string
+
+	root := Root("app")
+	child := Cmd("list").
+		ValidateArgs(ArbitraryArgs).
+		PreRun(func(ctx context.Context, c *Command) error {
+			seen = c.GetRawArgs()
+			return nil
+		})
+	root.AddCommand(child)
+
+	if err := root.ExecuteWithArgs([]string{"list", "a", "b"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 2 || seen[0] != "a" || seen[1] != "b" {
+		t.Errorf("expected [a b], got %v", seen)
+	}
+}
+
+// TestArgsValidatorWithSubcommands tests arguments alongside subcommands
+func TestArgsValidatorWithSubcommands(t *testing.T) {
+	var received []string
+
+	root := Root("app").
+		ValidateArgs(MaximumArgs(1)).
+		Action(func(ctx context.Context, c *Command, names ...string) error {
+			received = names
+			return nil
+		})
+	root.AddCommand(Cmd("deploy"))
+
+	if err := root.ExecuteWithArgs([]string{"target"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(received) != 1 || received[0] != "target" {
+		t.Errorf("expected [target], got %v", received)
+	}
+
+	t.Run("NoArgs reports unknown command", func(t *testing.T) {
+		root := Root("app").ValidateArgs(NoArgs)
+		root.AddCommand(Cmd("deploy"))
+
+		err := root.ExecuteWithArgs([]string{"deplyo"})
+		if _, ok := err.(*CommandNotFoundError); !ok {
+			t.Errorf("expected CommandNotFoundError, got %T", err)
+		}
+	})
+}
+
+// TestArgumentMetadataUsage tests usage rendering of argument metadata
+func TestArgumentMetadataUsage(t *testing.T) {
+	cmd := Root("app").
+		ArgChoices("env", "Environment", true, "dev", "prod").
+		ArgDefault("replicas", "Replica count", "1").
+		ArgVariadic("files", "Files to upload", false)
+
+	if got := cmd.argsUsage(); got != "<env> [replicas] [files...]" {
+		t.Errorf("unexpected usage %q", got)
+	}
+
+	t.Run("variadic inferred from action", func(t *testing.T) {
+		cmd := Root("app").
+			Arg("files", "Files", true).
+			Action(func(ctx context.Context, c *Command, files ...string) error {
+				return nil
+			})
+		if got := cmd.argsUsage(); got != "<files...>" {
+			t.Errorf("unexpected usage %q", got)
+		}
+	})
+}
+
+// TestArgumentVariadic tests variadic arguments declared with ArgVariadic
+func TestArgumentVariadic(t *testing.T) {
+	var received []string
+	cmd := Root("app").
+		Arg("dest", "Destination", true).
+		ArgVariadic("files", "Files", true).
+		Action(func(ctx context.Context, c *Command) error {
+			received = c.GetRawArgs()
+			return nil
+		})
+
+	if err := cmd.ExecuteWithArgs([]string{"out", "a", "b", "c"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(received) != 4 {
+		t.Errorf("expected 4 args, got %v", received)
+	}
+
+	err := cmd.ExecuteWithArgs([]string{"out"})
+	argErr, ok := err.(*ArgumentError)
+	if !ok {
+		t.Fatalf("expected ArgumentError, got %T", err)
+	}
+	if argErr.Arg != "files" {
+		t.Errorf("expected error for 'files', got %q", argErr.Arg)
+	}
+}
+
+// TestArgumentDefault tests default values for omitted arguments
+func TestArgumentDefault(t *testing.T) {
+	var env string
+	var replicas int
+	cmd := Root("app").
+		Arg("env", "Environment", true).
+		ArgDefault("replicas", "Replica count", "3").
+		Action(func(ctx context.Context, c *Command, e string, r int) error {
+			env, replicas = e, r
+			return nil
+		})
+
+	if err := cmd.ExecuteWithArgs([]string{"prod"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if env != "prod" || replicas != 3 {
+		t.Errorf("expected prod/3, got %s/%d", env, replicas)
+	}
+
+	if err := cmd.ExecuteWithArgs([]string{"prod", "5"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replicas != 5 {
+		t.Errorf("expected 5, got %d", replicas)
+	}
+}
+
+// TestArgumentChoicesAndType tests choices and type hint validation
+func TestArgumentChoicesAndType(t *testing.T) {
+	newCmd := func() *Command {
+		return Root("app").
+			ArgChoices("env", "Environment", true, "dev", "prod").
+			AddArg(Argument{Name: "timeout", Description: "Timeout", Type: "duration"}).
+			Action(func(ctx context.Context, c *Command) error {
+				return nil
+			})
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantArg string
+	}{
+		{"valid", []string{"dev", "5s"}, ""},
+		{"invalid choice", []string{"qa"}, "env"},
+		{"invalid type", []string{"prod", "soon"}, "timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newCmd().ExecuteWithArgs(tt.args)
+			if tt.wantArg == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			argErr, ok := err.(*ArgumentError)
+			if !ok {
+				t.Fatalf("expected ArgumentError, got %T", err)
+			}
+			if argErr.Arg != tt.wantArg {
+				t.Errorf("expected error for %q, got %q", tt.wantArg, argErr.Arg)
+			}
+		})
+	}
+}
+
+// TestArgsStructBinding tests binding positional arguments to struct fields
+func TestArgsStructBinding(t *testing.T) {
+	type copyArgs struct {
+		Src      string   `arg:"0,name=src,required" usage:"Source"`
+		Replicas *int     `arg:"1" usage:"Replica count"`
+		Mode     string   `arg:"2" default:"fast" choices:"fast|safe"`
+		Rest     []string `arg:"rest" usage:"Extra values"`
+		ignored  string
+	}
+
+	var a copyArgs
+	cmd := Root("cp").
+		Args(&a).
+		Action(func(ctx context.Context, c *Command) error {
+			return nil
+		})
+
+	args := cmd.GetArgs()
+	if len(args) != 4 {
+		t.Fatalf("expected 4 derived args, got %d", len(args))
+	}
+	if args[0].Name != "src" || !args[0].Required || args[0].Description != "Source" {
+		t.Errorf("unexpected first arg: %+v", args[0])
+	}
+	if args[2].Name != "mode" || args[2].Default != "fast" || len(args[2].Choices) != 2 {
+		t.Errorf("unexpected third arg: %+v", args[2])
+	}
+	if args[3].Name != "rest" || !args[3].Variadic {
+		t.Errorf("unexpected rest arg: %+v", args[3])
+	}
+	if got := cmd.argsUsage(); got != "<src> [replicas] [mode] [rest...]" {
+		t.Errorf("unexpected usage %q", got)
+	}
+
+	if err := cmd.ExecuteWithArgs([]string{"a.txt", "3", "safe", "x", "y"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.Src != "a.txt" || a.Replicas == nil || *a.Replicas != 3 || a.Mode != "safe" || len(a.Rest) != 2 {
+		t.Errorf("unexpected binding: %+v", a)
+	}
+
+	if err := cmd.ExecuteWithArgs([]string{"b.txt"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.Src != "b.txt" || a.Replicas != nil || a.Mode != "fast" || len(a.Rest) != 0 {
+		t.Errorf("omitted arguments should be reset or defaulted: %+v", a)
+	}
+
+	err := cmd.ExecuteWithArgs([]string{"c.txt", "many"})
+	argErr, ok := err.(*ArgumentError)
+	if !ok {
+		t.Fatalf("expected ArgumentError, got %T", err)
+	}
+	if argErr.Arg != "replicas" {
+		t.Errorf("expected error for 'replicas', got %q", argErr.Arg)
+	}
+
+	err = cmd.ExecuteWithArgs([]string{})
+	if argErr, ok := err.(*ArgumentError); !ok || argErr.Arg != "src" {
+		t.Errorf("expected missing 'src' error, got %v", err)
+	}
+}
+
+// TestArgsStructVariadicErrorName tests that errors in rest values carry the argument name
+func TestArgsStructVariadicErrorName(t *testing.T) {
+	var a struct {
+		Ports []int `arg:"rest"`
+	}
+	cmd := Root("app").Args(&a)
+
+	err := cmd.ExecuteWithArgs([]string{"80", "http"})
+	argErr, ok := err.(*ArgumentError)
+	if !ok {
+		t.Fatalf("expected ArgumentError, got %T", err)
+	}
+	if argErr.Arg != "ports" {
+		t.Errorf("expected error for 'ports', got %q", argErr.Arg)
+	}
+}
+
+// TestArgsStructInvalidTags tests that malformed arg tags panic at registration
+func TestArgsStructInvalidTags(t *testing.T) {
+	tests := []struct {
+		name string
+		ptr  interface{}
+	}{
+		{"not a pointer", struct{}{}},
+		{"gap in positions", &struct {
+			A string `arg:"0"`
+			B string `arg:"2"`
+		}{}},
+		{"rest not a slice", &struct {
+			A string `arg:"rest"`
+		}{}},
+		{"unknown option", &struct {
+			A string `arg:"0,optional"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			Cmd("test").Args(tt.ptr)
+		})
+	}
+}
+
+// TestKebabCase tests field name conversion for derived argument names
+func TestKebabCase(t *testing.T) {
+	tests := map[string]string{
+		"Src":        "src",
+		"SourceFile": "source-file",
+		"HTTPPort":   "http-port",
+		"ID":         "id",
+	}
+	for in, want := range tests {
+		if got := kebabCase(in); got != want {
+			t.Errorf("kebabCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
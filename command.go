@@ -3,6 +3,10 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/fs"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/nyxstack/color"
@@ -14,10 +18,53 @@ type Command struct {
 	description string
 	args        []Argument
 	flags       *FlagSet // All flags (automatically inherit to children)
+	inherited   *FlagSet // Cached view of own and ancestor flags (nil = rebuild)
 	subcommands map[string]*Command
 	parent      *Command
 	action      interface{}
+	invoker     *invoker // Action compiled when set
+	actionErr   error    // Problem with the action signature, reported on execution
+	handler     string   // Handler name when built from a spec
+	builtin     bool     // Added by the framework, e.g. the version command
 	hidden      bool
+	aliases     []string // Alternative names accepted for this command
+
+	// Lifecycle status
+	deprecated   string    // Deprecation message ("" = not deprecated)
+	experimental bool      // Marked as experimental in help
+	gateEnv      string    // Environment variable that enables the command
+	gateFlag     string    // Ancestor boolean flag that enables the command
+	stderr       io.Writer // Writer for diagnostics (nil = inherit)
+
+	// Subcommand listing
+	order  []string       // Subcommand names in declaration order
+	groups []CommandGroup // Titled groups for subcommands, in declaration order
+	group  string         // ID of the parent's group this command belongs to
+
+	defaultCommand string // Subcommand run when no subcommand is given
+
+	// Lazy construction (placeholders registered with AddLazyCommand)
+	lazy func() *Command // Builds the real command (nil = already built)
+
+	// Subcommand resolution (read from the root)
+	prefixMatching      bool // Accept unambiguous prefixes of subcommand names
+	suggestionsDisabled bool // Omit "Did you mean" suggestions from errors
+	suggestionDistance  int  // Maximum edit distance for suggestions (0 = default)
+	commandOrder        CommandOrder
+	pluginsEnabled      bool     // Run "<path>-<name>" executables for unknown subcommands
+	pluginDirs          []string // Directories searched for plugins before PATH
+	multiCall           bool     // Dispatch on the program name (os.Args[0])
+	useRegistry         bool     // Mount commands registered with Register on execution
+
+	// Positional argument handling
+	argValidators []ArgsValidator // Explicit argument policies (nil = infer from args and action)
+	rawArgs       []string        // Positional arguments of the current invocation
+	argFields     []argField      // Struct fields bound with Args
+
+	// File handling for path arguments and flags
+	fsys        fs.FS     // File system for path checks and inputs (nil = inherit)
+	stdin       io.Reader // Reader for "-" inputs (nil = inherit)
+	openedFiles []*Input  // Inputs opened for the current invocation
 
 	// Lifecycle hooks
 	persistentPreRun  func(context.Context, *Command) error
@@ -29,6 +76,19 @@ type Command struct {
 	helpEnabled bool
 	helpFlag    string
 	helpShort   string
+	helpTopics  []HelpTopic // Extra pages for the help command (root only)
+
+	// Version information (root only)
+	versionEnabled  bool
+	version         string // Version set in code, overriding build info
+	versionTemplate string // text/template for the version output ("" = default)
+
+	// Interactive shell (root only)
+	shellPrompt     string    // Prompt ("" = "<name>> ")
+	shellHistory    string    // History file
+	shellHistorySet bool      // shellHistory was set, even to "" (no file)
+	shellOut        io.Writer // Output of the running shell, for help and version
+	envExpansion    bool      // Expand environment variables in ExecuteLine and the shell
 }
 
 // Getter methods (public API)
@@ -52,6 +112,12 @@ func (c *Command) GetArgs() []Argument {
 	return c.args
 }
 
+// GetRawArgs returns the positional arguments of the current invocation,
+// with argument defaults filled in
+func (c *Command) GetRawArgs() []string {
+	return c.rawArgs
+}
+
 // Cmd creates a new command with the given name
 func Cmd(name string) *Command {
 	return &Command{
@@ -86,6 +152,59 @@ func (c *Command) IsHidden() bool {
 	return c.hidden
 }
 
+// Aliases sets alternative names for the command, e.g. "rm" for "remove".
+// On an attached command it panics if an alias is already used by a sibling.
+func (c *Command) Aliases(aliases ...string) *Command {
+	previous := c.aliases
+	c.aliases = aliases
+	if c.parent != nil {
+		if clash := c.parent.nameClash(c, nil); clash != "" {
+			c.aliases = previous
+			panic(fmt.Sprintf("cannot set aliases of '%s': %s", c.getCommandPath(), clash))
+		}
+	}
+	return c
+}
+
+// GetAliases returns the alternative names of the command
+func (c *Command) GetAliases() []string {
+	return c.aliases
+}
+
+// EnablePrefixMatching makes the command tree accept unambiguous prefixes of
+// subcommand names and aliases (e.g. "dep" for "deploy"). Set it on the root.
+// Prefixes are not matched where a command accepts positional arguments.
+func (c *Command) EnablePrefixMatching() *Command {
+	c.prefixMatching = true
+	return c
+}
+
+// DisablePrefixMatching requires exact subcommand names or aliases (default)
+func (c *Command) DisablePrefixMatching() *Command {
+	c.prefixMatching = false
+	return c
+}
+
+// EnableSuggestions adds "Did you mean" suggestions to unknown command and
+// flag errors (default). Set it on the root.
+func (c *Command) EnableSuggestions() *Command {
+	c.suggestionsDisabled = false
+	return c
+}
+
+// DisableSuggestions omits suggestions from unknown command and flag errors
+func (c *Command) DisableSuggestions() *Command {
+	c.suggestionsDisabled = true
+	return c
+}
+
+// SuggestionDistance sets the maximum edit distance between an unknown name
+// and a suggested one (default: 2). Set it on the root.
+func (c *Command) SuggestionDistance(distance int) *Command {
+	c.suggestionDistance = distance
+	return c
+}
+
 // Description sets the command description
 func (c *Command) Description(desc string) *Command {
 	c.description = desc
@@ -95,12 +214,14 @@ func (c *Command) Description(desc string) *Command {
 // Flag adds a typed flag to the command using reflection
 func (c *Command) Flag(ptr interface{}, name, shorthand string, defaultValue interface{}, usage string) *Command {
 	c.flags.Add(ptr, name, shorthand, defaultValue, usage)
+	c.invalidateFlags()
 	return c
 }
 
 // FlagRequired adds a required flag to the command
 func (c *Command) FlagRequired(ptr interface{}, name, shorthand string, defaultValue interface{}, usage string) *Command {
 	c.flags.Add(ptr, name, shorthand, defaultValue, usage)
+	c.invalidateFlags()
 	// Mark the flag as required
 	if flag := c.flags.GetFlag(name); flag != nil {
 		flag.required = true
@@ -111,6 +232,7 @@ func (c *Command) FlagRequired(ptr interface{}, name, shorthand string, defaultV
 // FlagHidden adds a hidden flag to the command
 func (c *Command) FlagHidden(ptr interface{}, name, shorthand string, defaultValue interface{}, usage string) *Command {
 	c.flags.Add(ptr, name, shorthand, defaultValue, usage)
+	c.invalidateFlags()
 	// Mark the flag as hidden
 	if flag := c.flags.GetFlag(name); flag != nil {
 		flag.hidden = true
@@ -118,9 +240,16 @@ func (c *Command) FlagHidden(ptr interface{}, name, shorthand string, defaultVal
 	return c
 }
 
+// GetFlag returns the flag with the given name visible to the command,
+// including inherited flags, or nil
+func (c *Command) GetFlag(name string) *Flag {
+	return c.inheritedFlags().GetFlag(name)
+}
+
 // Flags binds struct fields as flags using struct tags
 func (c *Command) Flags(structPtr interface{}) *Command {
 	c.flags.BindStruct(structPtr)
+	c.invalidateFlags()
 	return c
 }
 
@@ -134,9 +263,85 @@ func (c *Command) Arg(name, description string, required bool) *Command {
 	return c
 }
 
-// Action sets the function to execute when this command is run
+// Args binds struct fields as positional arguments using `arg` struct tags and
+// replaces the command's argument list with the one derived from the struct:
+//
+//	type CopyArgs struct {
+//		Src   cli.ExistingFile `arg:"0,name=src,required" usage:"Source file"`
+//		Dest  string           `arg:"1" usage:"Destination" default:"."`
+//		Extra []string         `arg:"rest" usage:"Extra files"`
+//	}
+func (c *Command) Args(structPtr interface{}) *Command {
+	c.args, c.argFields = parseArgStruct(structPtr)
+	return c
+}
+
+// ArgVariadic adds a positional argument that consumes all remaining
+// arguments. It must be the last argument; a required variadic argument
+// needs at least one value.
+func (c *Command) ArgVariadic(name, description string, required bool) *Command {
+	c.args = append(c.args, Argument{
+		Name:        name,
+		Description: description,
+		Required:    required,
+		Variadic:    true,
+	})
+	return c
+}
+
+// ArgDefault adds an optional positional argument with a default value
+func (c *Command) ArgDefault(name, description, defaultValue string) *Command {
+	c.args = append(c.args, Argument{
+		Name:        name,
+		Description: description,
+		Default:     defaultValue,
+	})
+	return c
+}
+
+// ArgChoices adds a positional argument restricted to the given values
+func (c *Command) ArgChoices(name, description string, required bool, choices ...string) *Command {
+	c.args = append(c.args, Argument{
+		Name:        name,
+		Description: description,
+		Required:    required,
+		Choices:     choices,
+	})
+	return c
+}
+
+// AddArg adds a fully specified positional argument
+func (c *Command) AddArg(arg Argument) *Command {
+	c.args = append(c.args, arg)
+	return c
+}
+
+// ValidateArgs sets the validators applied to positional arguments before any
+// hook runs. It replaces the default policy derived from Arg and the action.
+func (c *Command) ValidateArgs(validators ...ArgsValidator) *Command {
+	c.argValidators = validators
+	return c
+}
+
+// Action sets the function to execute when this command is run. The function
+// must have the form func(context.Context, *Command, args...) error; an invalid
+// signature is reported by Validate and when the command is executed.
 func (c *Command) Action(fn interface{}) *Command {
-	c.action = fn
+	c.action = nil
+	c.invoker = nil
+	c.actionErr = nil
+	if fn != nil {
+		inv, err := compileInvoker(fn)
+		if err != nil {
+			c.actionErr = &DefinitionError{
+				Msg: err.Error(),
+				Cmd: c,
+			}
+			return c
+		}
+		c.action = fn
+		c.invoker = inv
+	}
 	return c
 }
 
@@ -164,39 +369,162 @@ func (c *Command) PersistentPostRun(fn func(context.Context, *Command) error) *C
 	return c
 }
 
-// AddCommand adds a subcommand
+// AddCommand adds a subcommand. It panics if the name or an alias of cmd
+// is already used by another subcommand; use ReplaceCommand to swap one.
 func (c *Command) AddCommand(cmd *Command) *Command {
-	cmd.parent = c
-	c.subcommands[cmd.name] = cmd
+	if clash := c.nameClash(cmd, nil); clash != "" {
+		panic(fmt.Sprintf("cannot add command '%s' to '%s': %s", cmd.name, c.getCommandPath(), clash))
+	}
+	c.attach(cmd)
 	return c
 }
 
-// getAllFlags returns all flags including inherited from ancestors
-func (c *Command) getAllFlags() []*Flag {
-	var allFlags []*Flag
-	seen := make(map[string]bool) // Track by primary name to avoid duplicates
+// DefaultCommand sets the subcommand that runs when the command is invoked
+// without a subcommand (or with flags only), e.g. "list" for "server"
+func (c *Command) DefaultCommand(name string) *Command {
+	c.defaultCommand = name
+	return c
+}
+
+// GetDefaultCommand returns the default subcommand, or nil if none is set
+func (c *Command) GetDefaultCommand() *Command {
+	if c.defaultCommand == "" {
+		return nil
+	}
+	if cmd := c.subcommands[c.defaultCommand]; cmd != nil {
+		return cmd.materialize()
+	}
+	return nil
+}
 
-	// Collect flags from ancestors (root to current)
-	var ancestors []*Command
+// root returns the root of the command tree
+func (c *Command) root() *Command {
 	current := c
-	for current != nil {
-		ancestors = append([]*Command{current}, ancestors...)
+	for current.parent != nil {
 		current = current.parent
 	}
+	return current
+}
+
+// hasName reports whether name is the command's name or one of its aliases
+func (c *Command) hasName(name string) bool {
+	if c.name == name {
+		return true
+	}
+	for _, alias := range c.aliases {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupSubcommand returns the subcommand with the given name or alias,
+// without building lazy commands, or nil
+func (c *Command) lookupSubcommand(name string) *Command {
+	if cmd, exists := c.subcommands[name]; exists {
+		return cmd
+	}
+	for _, cmd := range c.sortedSubcommands() {
+		if cmd.hasName(name) {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// findSubcommand resolves a subcommand by name or alias and, when prefix
+// matching is enabled on the root, by unambiguous prefix. It returns nil if
+// nothing matches and an AmbiguousCommandError if a prefix matches several.
+func (c *Command) findSubcommand(name string) (*Command, error) {
+	if cmd := c.lookupSubcommand(name); cmd != nil {
+		return cmd.materialize(), nil
+	}
+
+	if !c.root().prefixMatching || c.acceptsArgs() {
+		return nil, nil
+	}
+
+	var match *Command
+	var candidates []string
+	for key, cmd := range c.subcommands {
+		if !cmd.isListed() {
+			continue
+		}
+		matched := strings.HasPrefix(key, name)
+		for _, alias := range cmd.aliases {
+			matched = matched || strings.HasPrefix(alias, name)
+		}
+		if matched {
+			match = cmd
+			candidates = append(candidates, key)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, nil
+	case 1:
+		return match.materialize(), nil
+	default:
+		sort.Strings(candidates)
+		return nil, &AmbiguousCommandError{
+			Name:       name,
+			Candidates: candidates,
+			Cmd:        c,
+		}
+	}
+}
+
+// getAllFlags returns all flags including inherited from ancestors.
+// The slice is shared with the cache and must not be modified.
+func (c *Command) getAllFlags() []*Flag {
+	return c.inheritedFlags().flags
+}
+
+// inheritedFlags returns the cached view of this command's flags and its
+// ancestors' flags, indexed by every name. Child flags shadow parent flags.
+func (c *Command) inheritedFlags() *FlagSet {
+	if c.inherited != nil {
+		return c.inherited
+	}
+
+	view := NewFlagSet()
+	seen := make(map[string]bool) // Track by primary name to avoid duplicates
 
 	// Add flags from current to root (child flags shadow parent flags)
-	for i := len(ancestors) - 1; i >= 0; i-- {
-		cmd := ancestors[i]
-		for _, flag := range cmd.flags.GetFlags() {
+	for current := c; current != nil; current = current.parent {
+		for _, flag := range current.flags.GetFlags() {
 			primaryName := flag.PrimaryName()
 			if !seen[primaryName] {
-				allFlags = append(allFlags, flag)
+				view.addFlag(flag)
 				seen[primaryName] = true
 			}
 		}
 	}
 
-	return allFlags
+	c.inherited = view
+	return view
+}
+
+// invalidateFlags drops the cached inherited flag views of this command and
+// its subcommands after flags were added or the command was reparented
+func (c *Command) invalidateFlags() {
+	c.inherited = nil
+	for _, subcmd := range c.subcommands {
+		subcmd.invalidateFlags()
+	}
+}
+
+// resetFlags restores the flags of the command and its subcommands to their
+// initial values, so the tree can be executed again
+func (c *Command) resetFlags() {
+	for _, flag := range c.flags.GetFlags() {
+		flag.reset()
+	}
+	for _, subcmd := range c.subcommands {
+		subcmd.resetFlags()
+	}
 }
 
 // getCommandPath returns the full command path from root to this command
@@ -207,6 +535,80 @@ func (c *Command) getCommandPath() string {
 	return c.parent.getCommandPath() + " " + c.name
 }
 
+// argsUsage returns the argument part of the usage line, e.g. "<env> [files...]"
+func (c *Command) argsUsage() string {
+	argList := []string{}
+	for i, arg := range c.args {
+		if i == c.variadicArgIndex() {
+			arg.Variadic = true
+		}
+		argList = append(argList, arg.usage())
+	}
+	return strings.Join(argList, " ")
+}
+
+// variadicArgIndex returns the index of the argument that consumes the
+// remaining values, declared with ArgVariadic or implied by a variadic
+// action whose last parameter lines up with the last argument. It returns -1
+// if there is none.
+func (c *Command) variadicArgIndex() int {
+	last := len(c.args) - 1
+	if last < 0 {
+		return -1
+	}
+	if c.args[last].Variadic {
+		return last
+	}
+	if c.invoker != nil {
+		actionType := c.invoker.fnType
+		if actionType.IsVariadic() && actionType.NumIn()-3 == last {
+			return last
+		}
+	}
+	return -1
+}
+
+// actionParamType returns the action parameter type receiving the value at
+// position i (the element type for variadic values), or nil if there is none
+func (c *Command) actionParamType(i int) reflect.Type {
+	if c.invoker == nil {
+		return nil
+	}
+	actionType := c.invoker.fnType
+	numParams := actionType.NumIn() - 2
+	if actionType.IsVariadic() && i >= numParams-1 {
+		return actionType.In(actionType.NumIn() - 1).Elem()
+	}
+	if i < 0 || i >= numParams {
+		return nil
+	}
+	return actionType.In(i + 2)
+}
+
+// argAt returns the argument definition for the value at position i,
+// or nil if there is none
+func (c *Command) argAt(i int) *Argument {
+	if i < len(c.args) {
+		return &c.args[i]
+	}
+	if v := c.variadicArgIndex(); v >= 0 {
+		return &c.args[v]
+	}
+	return nil
+}
+
+// usageLine returns the plain usage line used in error hints
+func (c *Command) usageLine() string {
+	usage := c.getCommandPath()
+	if c.hasListedSubcommands() {
+		usage += " [command]"
+	}
+	if argsUsage := c.argsUsage(); argsUsage != "" {
+		usage += " " + argsUsage
+	}
+	return usage
+}
+
 // DisableHelp disables the automatic help functionality
 func (c *Command) DisableHelp() *Command {
 	c.helpEnabled = false
@@ -233,59 +635,76 @@ func (c *Command) IsHelpEnabled() bool {
 
 // showHelp displays help information for the command
 func (c *Command) showHelp() {
+	w := c.stdoutWriter()
 	// Build the full command path for usage
 	commandPath := c.getCommandPath()
-	fmt.Printf("%s: %s", color.Bold+"Usage"+color.Reset, commandPath)
+	fmt.Fprintf(w, "%s: %s", color.Bold+"Usage"+color.Reset, commandPath)
 
 	// Show subcommands indicator first
-	if len(c.subcommands) > 0 {
-		fmt.Printf(" %s", color.Cyan+"[command]"+color.Reset)
+	if c.hasListedSubcommands() {
+		fmt.Fprintf(w, " %s", color.Cyan+"[command]"+color.Reset)
 	}
 
 	// Show arguments after subcommands
-	if len(c.args) > 0 {
-		argList := []string{}
-		for _, arg := range c.args {
-			if arg.Required {
-				argList = append(argList, fmt.Sprintf("<%s>", arg.Name))
-			} else {
-				argList = append(argList, fmt.Sprintf("[%s]", arg.Name))
-			}
-		}
-		if len(argList) > 0 {
-			fmt.Printf(" %s", color.Yellow+strings.Join(argList, " ")+color.Reset)
-		}
+	if argsUsage := c.argsUsage(); argsUsage != "" {
+		fmt.Fprintf(w, " %s", color.Yellow+argsUsage+color.Reset)
 	}
 
 	// Show flags indicator if any flags exist (local or inherited)
 	allFlags := c.getAllFlags()
 	if len(allFlags) > 0 || c.helpEnabled {
-		fmt.Printf(" %s", color.Dim+"[flags...]"+color.Reset)
+		fmt.Fprintf(w, " %s", color.Dim+"[flags...]"+color.Reset)
 	}
 
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	if c.description != "" {
-		fmt.Printf("\n%s\n", c.description)
+		fmt.Fprintf(w, "\n%s\n", c.description)
+	}
+
+	if len(c.aliases) > 0 {
+		fmt.Fprintf(w, "\n%s: %s\n", color.Bold+"Aliases"+color.Reset, strings.Join(c.aliases, ", "))
+	}
+
+	if c.deprecated != "" {
+		fmt.Fprintf(w, "\n%s: %s\n", color.Yellow+"Deprecated"+color.Reset, c.deprecated)
+	}
+	if c.experimental {
+		fmt.Fprintf(w, "\n%s\n", color.Yellow+"This command is experimental and may change without notice."+color.Reset)
 	}
 
 	// Show arguments with descriptions
 	if len(c.args) > 0 {
-		fmt.Printf("\n%s:\n", color.Bold+"Arguments"+color.Reset)
-		for _, arg := range c.args {
+		fmt.Fprintf(w, "\n%s:\n", color.Bold+"Arguments"+color.Reset)
+		for i, arg := range c.args {
+			name := arg.Name
+			if arg.Variadic || i == c.variadicArgIndex() {
+				name += "..."
+			}
+			if arg.Type != "" {
+				name += " " + arg.Type
+			}
+
 			required := ""
 			if arg.Required {
 				required = " " + color.Red + "(required)" + color.Reset
+			} else if arg.Default != "" {
+				required = " " + color.Dim + fmt.Sprintf("(default: %s)", arg.Default) + color.Reset
 			} else {
 				required = " " + color.Dim + "(optional)" + color.Reset
 			}
-			fmt.Printf("  %-15s %s%s\n", color.Yellow+arg.Name+color.Reset, arg.Description, required)
+
+			choices := ""
+			if len(arg.Choices) > 0 {
+				choices = " " + color.Dim + fmt.Sprintf("[%s]", strings.Join(arg.Choices, ", ")) + color.Reset
+			}
+			fmt.Fprintf(w, "  %-15s %s%s%s\n", color.Yellow+name+color.Reset, arg.Description, required, choices)
 		}
 	}
 
 	// Show all flags (local and inherited)
 	if len(allFlags) > 0 {
-		fmt.Printf("\n%s:\n", color.Bold+"Flags"+color.Reset)
+		fmt.Fprintf(w, "\n%s:\n", color.Bold+"Flags"+color.Reset)
 
 		// Track displayed flags by primary name to avoid duplicates
 		displayed := make(map[string]bool)
@@ -296,13 +715,8 @@ func (c *Command) showHelp() {
 
 			if !displayed[primaryName] {
 				// Determine if this is an inherited flag
-				isLocal := false
-				for _, localFlag := range c.flags.GetFlags() {
-					if localFlag.PrimaryName() == primaryName {
-						isLocal = true
-						break
-					}
-				}
+				localFlag := c.flags.GetFlag(primaryName)
+				isLocal := localFlag != nil && localFlag.PrimaryName() == primaryName
 
 				suffix := ""
 				if !isLocal && c.parent != nil {
@@ -317,47 +731,62 @@ func (c *Command) showHelp() {
 		// Add help flag if enabled
 		if c.helpEnabled {
 			helpNames := fmt.Sprintf("%s, %s", color.Green+fmt.Sprintf("-%s", c.helpShort)+color.Reset, color.Green+fmt.Sprintf("--%s", c.helpFlag)+color.Reset)
-			fmt.Printf("  %-30s %s\n", helpNames, "Show help information")
+			fmt.Fprintf(w, "  %-30s %s\n", helpNames, "Show help information")
 		}
-	} else if c.helpEnabled {
-		// Show help flag even if no other flags
-		fmt.Printf("\n%s:\n", color.Bold+"Flags"+color.Reset)
-		helpNames := fmt.Sprintf("%s, %s", color.Green+fmt.Sprintf("-%s", c.helpShort)+color.Reset, color.Green+fmt.Sprintf("--%s", c.helpFlag)+color.Reset)
-		fmt.Printf("  %-30s %s\n", helpNames, "Show help information")
-	}
-
-	// Show subcommands
-	if len(c.subcommands) > 0 {
-		// Count visible subcommands
-		visibleCount := 0
-		for _, cmd := range c.subcommands {
-			if !cmd.IsHidden() {
-				visibleCount++
-			}
+		c.showVersionFlag()
+	} else if c.helpEnabled || c.hasVersionFlag() {
+		// Show built-in flags even if no other flags
+		fmt.Fprintf(w, "\n%s:\n", color.Bold+"Flags"+color.Reset)
+		if c.helpEnabled {
+			helpNames := fmt.Sprintf("%s, %s", color.Green+fmt.Sprintf("-%s", c.helpShort)+color.Reset, color.Green+fmt.Sprintf("--%s", c.helpFlag)+color.Reset)
+			fmt.Fprintf(w, "  %-30s %s\n", helpNames, "Show help information")
 		}
+		c.showVersionFlag()
+	}
 
-		if visibleCount > 0 {
-			fmt.Printf("\n%s:\n", color.Bold+"Commands"+color.Reset)
-			for name, cmd := range c.subcommands {
-				if !cmd.IsHidden() {
-					fmt.Printf("  %-15s %s\n", color.Cyan+name+color.Reset, cmd.description)
-				}
+	// Show subcommands, grouped and in the configured order
+	sections := c.groupedSubcommands()
+	for _, section := range sections {
+		fmt.Fprintf(w, "\n%s:\n", color.Bold+section.title+color.Reset)
+		for _, cmd := range section.commands {
+			notes := ""
+			if cmd.name == c.defaultCommand {
+				notes += " " + color.Dim + "(default)" + color.Reset
 			}
-
-			// Show help command if enabled
-			if c.helpEnabled {
-				fmt.Printf("\n%s \"%s [command] %s\" %s\n",
-					color.Dim+"Use"+color.Reset,
-					c.name,
-					color.Green+"--"+c.helpFlag+color.Reset,
-					color.Dim+"for more information about a command."+color.Reset)
+			if cmd.experimental {
+				notes += " " + color.Yellow + "(experimental)" + color.Reset
 			}
+			if len(cmd.aliases) > 0 {
+				notes += " " + color.Dim + fmt.Sprintf("(aliases: %s)", strings.Join(cmd.aliases, ", ")) + color.Reset
+			}
+			fmt.Fprintf(w, "  %-15s %s%s\n", color.Cyan+cmd.name+color.Reset, cmd.description, notes)
+		}
+	}
+
+	// Show external plugins
+	plugins := c.GetPlugins()
+	if len(plugins) > 0 {
+		fmt.Fprintf(w, "\n%s:\n", color.Bold+"Plugins"+color.Reset)
+		for _, plugin := range plugins {
+			fmt.Fprintf(w, "  %-15s %s\n", color.Cyan+plugin.Name+color.Reset, plugin.Description)
 		}
 	}
+
+	// Show help command if enabled
+	if (len(sections) > 0 || len(plugins) > 0) && c.helpEnabled {
+		fmt.Fprintf(w, "\n%s \"%s [command] %s\" %s\n",
+			color.Dim+"Use"+color.Reset,
+			c.name,
+			color.Green+"--"+c.helpFlag+color.Reset,
+			color.Dim+"for more information about a command."+color.Reset)
+	}
+
+	c.showHelpTopics()
 }
 
 // displayFlag formats and displays a single flag
 func (c *Command) displayFlag(flag *Flag, suffix string) {
+	w := c.stdoutWriter()
 	names := color.Green + fmt.Sprintf("--%s", flag.PrimaryName()) + color.Reset
 	if flag.ShortName() != "" {
 		names = fmt.Sprintf("%s, %s", color.Green+fmt.Sprintf("-%s", flag.ShortName())+color.Reset, names)
@@ -368,7 +797,7 @@ func (c *Command) displayFlag(flag *Flag, suffix string) {
 		defaultInfo = color.Dim + fmt.Sprintf(" (default: %v)", flag.GetDefault()) + color.Reset
 	}
 
-	fmt.Printf("  %-30s %s%s%s\n", names, flag.GetUsage(), defaultInfo, suffix)
+	fmt.Fprintf(w, "  %-30s %s%s%s\n", names, flag.GetUsage(), defaultInfo, suffix)
 }
 
 // ShowHelp displays help information (public API)
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"strings"
 	"testing"
 )
 
@@ -183,3 +184,150 @@ func TestCommandChaining(t *testing.T) {
 		t.Error("chaining broke args")
 	}
 }
+
+// TestCommandAliases tests resolving subcommands by alias
+func TestCommandAliases(t *testing.T) {
+	var ran string
+	root := Root("app")
+	remove := Cmd("remove").
+		Aliases("rm", "del").
+		Action(func(ctx context.Context, c *Command) error {
+			ran = c.GetName()
+			return nil
+		})
+	root.AddCommand(remove)
+
+	if got := remove.GetAliases(); len(got) != 2 || got[0] != "rm" {
+		t.Errorf("unexpected aliases: %v", got)
+	}
+
+	for _, name := range []string{"remove", "rm", "del"} {
+		ran = ""
+		if err := root.ExecuteWithArgs([]string{name}); err != nil {
+			t.Fatalf("%s: execution failed: %v", name, err)
+		}
+		if ran != "remove" {
+			t.Errorf("%s: expected remove to run, got %q", name, ran)
+		}
+	}
+
+	// Prefixes are not matched unless enabled
+	if err := root.ExecuteWithArgs([]string{"rem"}); err == nil {
+		t.Error("expected error for prefix without prefix matching")
+	}
+
+	// Aliases set after the command is attached are checked against siblings
+	rename := Cmd("rename")
+	root.AddCommand(rename)
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for an alias used by a sibling")
+		}
+		if len(rename.GetAliases()) != 0 {
+			t.Errorf("rejected aliases should not be kept, got %v", rename.GetAliases())
+		}
+	}()
+	rename.Aliases("mv", "remove")
+}
+
+// TestCommandPrefixMatching tests unique-prefix resolution and ambiguity errors
+func TestCommandPrefixMatching(t *testing.T) {
+	var ran string
+	action := func(ctx context.Context, c *Command) error {
+		ran = c.GetName()
+		return nil
+	}
+	root := Root("app").EnablePrefixMatching()
+	root.AddCommand(Cmd("deploy").Action(action))
+	root.AddCommand(Cmd("describe").Action(action))
+	root.AddCommand(Cmd("status").Aliases("st").Action(action))
+	root.AddCommand(Cmd("secret").Hidden().Action(action))
+
+	if err := root.ExecuteWithArgs([]string{"dep"}); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if ran != "deploy" {
+		t.Errorf("expected deploy, got %q", ran)
+	}
+
+	// Hidden commands are not prefix candidates, so "s" is unambiguous
+	if err := root.ExecuteWithArgs([]string{"s"}); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if ran != "status" {
+		t.Errorf("expected status, got %q", ran)
+	}
+
+	err := root.ExecuteWithArgs([]string{"de"})
+	ambiguous, ok := err.(*AmbiguousCommandError)
+	if !ok {
+		t.Fatalf("expected AmbiguousCommandError, got %T: %v", err, err)
+	}
+	if len(ambiguous.Candidates) != 2 || ambiguous.Candidates[0] != "deploy" || ambiguous.Candidates[1] != "describe" {
+		t.Errorf("unexpected candidates: %v", ambiguous.Candidates)
+	}
+
+	root.DisablePrefixMatching()
+	if err := root.ExecuteWithArgs([]string{"dep"}); err == nil {
+		t.Error("expected error after disabling prefix matching")
+	}
+}
+
+// TestDefaultCommand tests dispatching to the default subcommand
+func TestDefaultCommand(t *testing.T) {
+	var ran string
+	var all bool
+	root := Root("app")
+	server := Cmd("server").DefaultCommand("list")
+	server.AddCommand(Cmd("list").
+		Flag(&all, "all", "a", false, "Show all").
+		Action(func(ctx context.Context, c *Command) error {
+			ran = c.GetName()
+			return nil
+		}))
+	server.AddCommand(Cmd("start").Action(func(ctx context.Context, c *Command) error {
+		ran = c.GetName()
+		return nil
+	}))
+	root.AddCommand(server)
+
+	if server.GetDefaultCommand() == nil || server.GetDefaultCommand().GetName() != "list" {
+		t.Fatal("default command should be list")
+	}
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"server"}, "list"},
+		{[]string{"server", "--all"}, "list"},
+		{[]string{"server", "start"}, "start"},
+	}
+	for _, tt := range tests {
+		ran = ""
+		if err := root.ExecuteWithArgs(tt.args); err != nil {
+			t.Fatalf("%v: execution failed: %v", tt.args, err)
+		}
+		if ran != tt.want {
+			t.Errorf("%v: expected %s, got %q", tt.args, tt.want, ran)
+		}
+	}
+	if !all {
+		t.Error("flags should be passed to the default command")
+	}
+
+	// Positional arguments are not swallowed by the default
+	if _, ok := root.ExecuteWithArgs([]string{"server", "stop"}).(*CommandNotFoundError); !ok {
+		t.Error("expected CommandNotFoundError for unknown subcommand")
+	}
+
+	out := captureStdout(t, server.ShowHelp)
+	if !strings.Contains(out, "(default)") {
+		t.Errorf("help should mark the default command:\n%s", out)
+	}
+
+	root.AddCommand(Cmd("broken").DefaultCommand("missing"))
+	if err := root.Validate(); err == nil || !strings.Contains(err.Error(), `default command "missing"`) {
+		t.Errorf("expected Validate to report the missing default, got %v", err)
+	}
+}
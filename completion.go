@@ -1,5 +1,7 @@
 package cli
 
+import "strings"
+
 // ShellCompletion interface for different shell implementations
 type ShellCompletion interface {
 	// GetCompletions returns completion suggestions for a command
@@ -14,6 +16,9 @@ type ShellCompletion interface {
 
 // AddCompletion registers completion commands for all supported shells
 func AddCompletion(rootCmd *Command) {
+	// Include registered commands; problems are reported by Execute
+	rootCmd.mountRegistry()
+
 	// Register bash completion
 	bashComp := &BashCompletion{}
 	bashComp.Register(rootCmd)
@@ -31,15 +36,58 @@ func AddCompletion(rootCmd *Command) {
 	psComp.Register(rootCmd)
 }
 
-// getCompletionWords returns completion words for a command (shared implementation)
-func getCompletionWords(cmd *Command) []string {
+// completionTarget resolves the command that words typed after cmd belong
+// to, and the positional arguments already given to it. Flags are skipped.
+func completionTarget(cmd *Command, words []string) (*Command, []string) {
+	var positional []string
+	for _, word := range words {
+		if strings.HasPrefix(word, "-") {
+			continue
+		}
+		if len(positional) == 0 {
+			if subcmd, _ := cmd.findSubcommand(word); subcmd != nil && subcmd.gateOpen(nil) {
+				cmd = subcmd
+				continue
+			}
+		}
+		positional = append(positional, word)
+	}
+	return cmd, positional
+}
+
+// getCompletionWords returns completion words for a command (shared
+// implementation). args are the positional arguments already typed after
+// the command.
+func getCompletionWords(cmd *Command, args []string) []string {
 	var words []string
 
-	// Add visible subcommands
-	for name, subcmd := range cmd.GetCommands() {
-		if !subcmd.IsHidden() {
-			words = append(words, name)
+	// Add visible subcommands (only before the first positional argument)
+	if len(args) == 0 {
+		for _, subcmd := range cmd.sortedSubcommands() {
+			if subcmd.isListed() {
+				words = append(words, subcmd.name)
+				words = append(words, subcmd.aliases...)
+			}
+		}
+		for _, plugin := range cmd.GetPlugins() {
+			words = append(words, plugin.Name)
 		}
+		if cmd.hasHelpCommand() {
+			words = append(words, cmd.helpFlag)
+		}
+	}
+
+	// Add allowed values of the next positional argument
+	if arg := cmd.argAt(len(args)); arg != nil {
+		words = append(words, arg.Choices...)
+	}
+
+	// Ask the shell for file or directory completion on path arguments
+	switch cmd.argPathHint(len(args)) {
+	case "file":
+		words = append(words, completeFilesDirective)
+	case "dir":
+		words = append(words, completeDirsDirective)
 	}
 
 	// Add all available flags
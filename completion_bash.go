@@ -9,17 +9,17 @@ import (
 type BashCompletion struct{}
 
 func (b *BashCompletion) GetCompletions(cmd *Command, args []string) []string {
-	return getCompletionWords(cmd)
+	return getCompletionWords(cmd, args)
 }
 
 func (b *BashCompletion) Register(cmd *Command) {
 	bashCmd := Cmd("__bashcomplete").
 		Description("Bash completion helper").
 		Hidden().
-		Action(func(ctx context.Context, bashCommand *Command) error {
-			targetCmd := bashCommand.GetParent()
-			// For completion, we don't need args since we complete the parent
-			words := b.GetCompletions(targetCmd, nil)
+		Action(func(ctx context.Context, bashCommand *Command, typed ...string) error {
+			// The words typed after the parent select the command to complete
+			targetCmd, args := completionTarget(bashCommand.GetParent(), typed)
+			words := b.GetCompletions(targetCmd, args)
 
 			for _, word := range words {
 				fmt.Println(word)
@@ -27,14 +27,7 @@ func (b *BashCompletion) Register(cmd *Command) {
 			return nil
 		})
 
-	cmd.AddCommand(bashCmd)
-
-	// Recursively register for all subcommands
-	for _, subcmd := range cmd.GetCommands() {
-		if !subcmd.IsHidden() {
-			b.Register(subcmd)
-		}
-	}
+	cmd.ReplaceCommand(bashCmd)
 }
 
 func (b *BashCompletion) GenerateScript(cmd *Command) string {
@@ -48,20 +41,29 @@ _%s_completion() {
     local cur prev words cword
     _init_completion || return
 
-    # Get the full command path
-    local cmd_path="${COMP_WORDS[0]}"
+    # Collect the words typed before the current one (flags are skipped)
+    local typed=()
     for ((i=1; i < COMP_CWORD; i++)); do
         local word="${COMP_WORDS[i]}"
         if [[ "$word" != -* ]]; then
-            cmd_path="$cmd_path $word"
+            typed+=("$word")
         fi
     done
 
-    # Get completions from the command
-    local completions=$($cmd_path __bashcomplete 2>/dev/null)
+    # Get completions from the program
+    local completions=$("${COMP_WORDS[0]}" __bashcomplete "${typed[@]}" 2>/dev/null)
+    local words="${completions//:files/}"
+    words="${words//:dirs/}"
     
     # Generate reply
-    COMPREPLY=($(compgen -W "$completions" -- "$cur"))
+    COMPREPLY=($(compgen -W "$words" -- "$cur"))
+
+    # Path arguments use native file and directory completion
+    if [[ "$completions" == *":files"* ]]; then
+        COMPREPLY+=($(compgen -f -- "$cur"))
+    elif [[ "$completions" == *":dirs"* ]]; then
+        COMPREPLY+=($(compgen -d -- "$cur"))
+    fi
 }
 
 complete -F _%s_completion %s
@@ -9,17 +9,17 @@ import (
 type FishCompletion struct{}
 
 func (f *FishCompletion) GetCompletions(cmd *Command, args []string) []string {
-	return getCompletionWords(cmd)
+	return getCompletionWords(cmd, args)
 }
 
 func (f *FishCompletion) Register(cmd *Command) {
 	fishCmd := Cmd("__fishcomplete").
 		Description("Fish completion helper").
 		Hidden().
-		Action(func(ctx context.Context, fishCommand *Command) error {
-			targetCmd := fishCommand.GetParent()
-			// For completion, we don't need args since we complete the parent
-			words := f.GetCompletions(targetCmd, nil)
+		Action(func(ctx context.Context, fishCommand *Command, typed ...string) error {
+			// The words typed after the parent select the command to complete
+			targetCmd, args := completionTarget(fishCommand.GetParent(), typed)
+			words := f.GetCompletions(targetCmd, args)
 
 			for _, word := range words {
 				fmt.Println(word)
@@ -27,14 +27,7 @@ func (f *FishCompletion) Register(cmd *Command) {
 			return nil
 		})
 
-	cmd.AddCommand(fishCmd)
-
-	// Recursively register for all subcommands
-	for _, subcmd := range cmd.GetCommands() {
-		if !subcmd.IsHidden() {
-			f.Register(subcmd)
-		}
-	}
+	cmd.ReplaceCommand(fishCmd)
 }
 
 func (f *FishCompletion) GenerateScript(cmd *Command) string {
@@ -44,8 +37,20 @@ func (f *FishCompletion) GenerateScript(cmd *Command) string {
 # Save this to ~/.config/fish/completions/%s.fish
 
 function __%s_complete
-    set -l cmd_path (commandline -opc)
-    $cmd_path __fishcomplete 2>/dev/null
+    # Words typed before the current one (flags are skipped)
+    set -l tokens (commandline -opc)
+    set -l typed (string match -v -- '-*' $tokens[2..-1])
+    for word in ($tokens[1] __fishcomplete $typed 2>/dev/null)
+        # Path arguments use native file and directory completion
+        switch $word
+            case :files
+                __fish_complete_path (commandline -ct)
+            case :dirs
+                __fish_complete_directories (commandline -ct)
+            case '*'
+                echo $word
+        end
+    end
 end
 
 complete -c %s -f -a "(__%s_complete)"
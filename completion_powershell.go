@@ -9,17 +9,17 @@ import (
 type PowerShellCompletion struct{}
 
 func (p *PowerShellCompletion) GetCompletions(cmd *Command, args []string) []string {
-	return getCompletionWords(cmd)
+	return getCompletionWords(cmd, args)
 }
 
 func (p *PowerShellCompletion) Register(cmd *Command) {
 	psCmd := Cmd("__powershellcomplete").
 		Description("PowerShell completion helper").
 		Hidden().
-		Action(func(ctx context.Context, psCommand *Command) error {
-			targetCmd := psCommand.GetParent()
-			// For completion, we don't need args since we complete the parent
-			words := p.GetCompletions(targetCmd, nil)
+		Action(func(ctx context.Context, psCommand *Command, typed ...string) error {
+			// The words typed after the parent select the command to complete
+			targetCmd, args := completionTarget(psCommand.GetParent(), typed)
+			words := p.GetCompletions(targetCmd, args)
 
 			for _, word := range words {
 				fmt.Println(word)
@@ -27,14 +27,7 @@ func (p *PowerShellCompletion) Register(cmd *Command) {
 			return nil
 		})
 
-	cmd.AddCommand(psCmd)
-
-	// Recursively register for all subcommands
-	for _, subcmd := range cmd.GetCommands() {
-		if !subcmd.IsHidden() {
-			p.Register(subcmd)
-		}
-	}
+	cmd.ReplaceCommand(psCmd)
 }
 
 func (p *PowerShellCompletion) GenerateScript(cmd *Command) string {
@@ -47,22 +40,37 @@ func (p *PowerShellCompletion) GenerateScript(cmd *Command) string {
 Register-ArgumentCompleter -Native -CommandName %s -ScriptBlock {
     param($wordToComplete, $commandAst, $cursorPosition)
     
-    $cmdPath = $commandAst.CommandElements[0].Value
+    $program = $commandAst.CommandElements[0].Extent.Text
     
-    # Build command path from AST
+    # Collect the words typed before the current one (flags are skipped)
+    $typed = @()
     for ($i = 1; $i -lt $commandAst.CommandElements.Count; $i++) {
-        $element = $commandAst.CommandElements[$i].Value
-        if (-not $element.StartsWith('-')) {
-            $cmdPath = "$cmdPath $element"
+        $element = $commandAst.CommandElements[$i].Extent
+        if ($element.EndOffset -ge $cursorPosition) {
+            break
+        }
+        if (-not $element.Text.StartsWith('-')) {
+            $typed += $element.Text
         }
     }
     
     # Get completions
-    $completions = & $cmdPath __powershellcomplete 2>$null
+    $completions = & $program __powershellcomplete @typed 2>$null
     
-    $completions | ForEach-Object {
+    $completions | Where-Object { $_ -ne ':files' -and $_ -ne ':dirs' } | ForEach-Object {
         [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
     }
+
+    # Path arguments use native file and directory completion
+    if ($completions -contains ':files') {
+        Get-ChildItem -Path "$wordToComplete*" | ForEach-Object {
+            [System.Management.Automation.CompletionResult]::new($_.Name, $_.Name, 'ProviderItem', $_.Name)
+        }
+    } elseif ($completions -contains ':dirs') {
+        Get-ChildItem -Path "$wordToComplete*" -Directory | ForEach-Object {
+            [System.Management.Automation.CompletionResult]::new($_.Name, $_.Name, 'ProviderContainer', $_.Name)
+        }
+    }
 }
 `, cmdName, cmdName, cmdName)
 
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"strings"
 	"testing"
 )
@@ -157,8 +158,9 @@ func TestCompletionHiddenCommandsExcluded(t *testing.T) {
 	}
 }
 
-// TestCompletionRegistersSubcommands tests that completion registers for all subcommands
-func TestCompletionRegistersSubcommands(t *testing.T) {
+// TestCompletionRegistersOnRoot tests that completion helpers are only
+// registered on the given command
+func TestCompletionRegistersOnRoot(t *testing.T) {
 	root := Root("myapp")
 
 	deploy := Cmd("deploy")
@@ -175,12 +177,12 @@ func TestCompletionRegistersSubcommands(t *testing.T) {
 		t.Error("root should have __bashcomplete command")
 	}
 
-	// Check that __bashcomplete was added to subcommands
-	if _, exists := deploy.subcommands["__bashcomplete"]; !exists {
-		t.Error("deploy should have __bashcomplete command")
+	// Subcommands are completed through the root's helper
+	if _, exists := deploy.subcommands["__bashcomplete"]; exists {
+		t.Error("deploy should not have __bashcomplete command")
 	}
-	if _, exists := database.subcommands["__bashcomplete"]; !exists {
-		t.Error("database should have __bashcomplete command")
+	if _, exists := database.subcommands["__bashcomplete"]; exists {
+		t.Error("database should not have __bashcomplete command")
 	}
 }
 
@@ -205,8 +207,89 @@ func TestAddCompletion(t *testing.T) {
 		if _, exists := root.subcommands[cmdName]; !exists {
 			t.Errorf("root should have %s command", cmdName)
 		}
-		if _, exists := deploy.subcommands[cmdName]; !exists {
-			t.Errorf("deploy should have %s command", cmdName)
+		if _, exists := deploy.subcommands[cmdName]; exists {
+			t.Errorf("deploy should not have %s command", cmdName)
 		}
 	}
 }
+
+// TestCompletionArgumentChoices tests that argument choices are offered for completion
+func TestCompletionArgumentChoices(t *testing.T) {
+	root := Root("myapp").
+		ArgChoices("env", "Environment", true, "dev", "prod").
+		ArgChoices("region", "Region", false, "eu", "us")
+
+	bash := &BashCompletion{}
+
+	first := bash.GetCompletions(root, nil)
+	if !containsWord(first, "dev") || !containsWord(first, "prod") {
+		t.Errorf("expected env choices, got %v", first)
+	}
+	if containsWord(first, "eu") {
+		t.Errorf("region choices should not be offered for the first argument, got %v", first)
+	}
+
+	second := bash.GetCompletions(root, []string{"dev"})
+	if !containsWord(second, "eu") || containsWord(second, "dev") {
+		t.Errorf("expected region choices, got %v", second)
+	}
+}
+
+func containsWord(words []string, word string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
+
+// TestCompletionIncludesAliases tests that aliases of visible subcommands are offered
+func TestCompletionIncludesAliases(t *testing.T) {
+	root := Root("app")
+	root.AddCommand(Cmd("remove").Aliases("rm"))
+	root.AddCommand(Cmd("secret").Aliases("sec").Hidden())
+
+	words := getCompletionWords(root, nil)
+	if !containsWord(words, "remove") || !containsWord(words, "rm") {
+		t.Errorf("expected remove and rm, got %v", words)
+	}
+	if containsWord(words, "sec") {
+		t.Errorf("hidden command aliases should not be offered, got %v", words)
+	}
+}
+
+// TestCompletionHelpersWithTypedArgs tests the completion helpers as the
+// scripts run them, with the typed words as arguments
+func TestCompletionHelpersWithTypedArgs(t *testing.T) {
+	ran := false
+	root := Root("myapp")
+	root.AddCommand(Cmd("deploy").
+		ArgChoices("env", "Environment", true, "dev", "prod").
+		ArgChoices("region", "Region", false, "eu", "us").
+		Action(func(ctx context.Context, c *Command, env, region string) error {
+			ran = true
+			return nil
+		}))
+	AddCompletion(root)
+
+	for _, helper := range []string{"__bashcomplete", "__zshcomplete", "__fishcomplete", "__powershellcomplete"} {
+		out := captureStdout(t, func() {
+			if err := root.ExecuteWithArgs([]string{helper, "deploy", "dev"}); err != nil {
+				t.Errorf("%s failed: %v", helper, err)
+			}
+		})
+		words := strings.Fields(out)
+		if !containsWord(words, "eu") || containsWord(words, "dev") {
+			t.Errorf("%s: expected region choices, got %v", helper, words)
+		}
+
+		out = captureStdout(t, func() { root.ExecuteWithArgs([]string{helper, "deploy"}) })
+		if words := strings.Fields(out); !containsWord(words, "prod") {
+			t.Errorf("%s: expected env choices, got %v", helper, words)
+		}
+	}
+	if ran {
+		t.Error("completion must not run the command's action")
+	}
+}
@@ -9,17 +9,17 @@ import (
 type ZshCompletion struct{}
 
 func (z *ZshCompletion) GetCompletions(cmd *Command, args []string) []string {
-	return getCompletionWords(cmd)
+	return getCompletionWords(cmd, args)
 }
 
 func (z *ZshCompletion) Register(cmd *Command) {
 	zshCmd := Cmd("__zshcomplete").
 		Description("Zsh completion helper").
 		Hidden().
-		Action(func(ctx context.Context, zshCommand *Command) error {
-			targetCmd := zshCommand.GetParent()
-			// For completion, we don't need args since we complete the parent
-			words := z.GetCompletions(targetCmd, nil)
+		Action(func(ctx context.Context, zshCommand *Command, typed ...string) error {
+			// The words typed after the parent select the command to complete
+			targetCmd, args := completionTarget(zshCommand.GetParent(), typed)
+			words := z.GetCompletions(targetCmd, args)
 
 			for _, word := range words {
 				fmt.Println(word)
@@ -27,14 +27,7 @@ func (z *ZshCompletion) Register(cmd *Command) {
 			return nil
 		})
 
-	cmd.AddCommand(zshCmd)
-
-	// Recursively register for all subcommands
-	for _, subcmd := range cmd.GetCommands() {
-		if !subcmd.IsHidden() {
-			z.Register(subcmd)
-		}
-	}
+	cmd.ReplaceCommand(zshCmd)
 }
 
 func (z *ZshCompletion) GenerateScript(cmd *Command) string {
@@ -48,18 +41,26 @@ func (z *ZshCompletion) GenerateScript(cmd *Command) string {
 #   compinit
 
 _%s() {
-    local -a completions
-    local cmd_path="${words[1]}"
+    local -a completions typed
     
-    # Build command path from words
+    # Collect the words typed before the current one (flags are skipped)
     for ((i=2; i < CURRENT; i++)); do
         if [[ "${words[i]}" != -* ]]; then
-            cmd_path="$cmd_path ${words[i]}"
+            typed+=("${words[i]}")
         fi
     done
     
     # Get completions
-    completions=(${(f)"$($cmd_path __zshcomplete 2>/dev/null)"})
+    completions=(${(f)"$(${words[1]} __zshcomplete "${typed[@]}" 2>/dev/null)"})
+
+    # Path arguments use native file and directory completion
+    if (( ${completions[(I):files]} )); then
+        _files
+    elif (( ${completions[(I):dirs]} )); then
+        _files -/
+    fi
+    completions=(${completions:#:files})
+    completions=(${completions:#:dirs})
     
     _describe '%s' completions
 }
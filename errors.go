@@ -1,35 +1,126 @@
 package cli
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // CommandNotFoundError indicates a subcommand was not found
 type CommandNotFoundError struct {
-	Name string
-	Cmd  *Command
+	Name        string
+	Cmd         *Command
+	Suggestions []string // Similar visible subcommands, closest first
+	Hint        string   // How to enable a gated command, if Name is one
 }
 
 func (e *CommandNotFoundError) Error() string {
-	return fmt.Sprintf("unknown command '%s' for '%s'", e.Name, e.Cmd.getCommandPath())
+	msg := fmt.Sprintf("unknown command '%s' for '%s'", e.Name, e.Cmd.getCommandPath())
+	if e.Hint != "" {
+		msg += fmt.Sprintf(" (%s)", e.Hint)
+	}
+	return msg
+}
+
+// AmbiguousCommandError indicates a prefix matched several subcommands
+type AmbiguousCommandError struct {
+	Name       string
+	Candidates []string
+	Cmd        *Command
+}
+
+func (e *AmbiguousCommandError) Error() string {
+	return fmt.Sprintf("ambiguous command '%s' for '%s', could be: %s", e.Name, e.Cmd.getCommandPath(), strings.Join(e.Candidates, ", "))
 }
 
 // ArgumentError indicates an argument validation error
 type ArgumentError struct {
-	Arg string
-	Msg string
-	Cmd *Command
+	Arg   string
+	Msg   string
+	Cmd   *Command
+	Usage string // Usage line of the command, e.g. "app deploy <env> [replicas]"
 }
 
 func (e *ArgumentError) Error() string {
-	return fmt.Sprintf("argument '%s': %s", e.Arg, e.Msg)
+	msg := fmt.Sprintf("argument '%s': %s", e.Arg, e.Msg)
+	if e.Usage != "" {
+		msg += fmt.Sprintf(" (usage: %s)", e.Usage)
+	}
+	return msg
 }
 
 // FlagError indicates a flag parsing or validation error
 type FlagError struct {
-	Flag string
-	Msg  string
-	Cmd  *Command
+	Flag        string
+	Msg         string
+	Cmd         *Command
+	Suggestions []string // Similar visible flags for an unknown flag, e.g. "--verbose"
 }
 
 func (e *FlagError) Error() string {
 	return fmt.Sprintf("flag '%s': %s", e.Flag, e.Msg)
 }
+
+// DefinitionError indicates an invalid command definition, such as an action
+// with an unsupported signature, detected at registration or by Validate
+type DefinitionError struct {
+	Msg string
+	Cmd *Command
+}
+
+func (e *DefinitionError) Error() string {
+	return fmt.Sprintf("invalid definition of '%s': %s", e.Cmd.getCommandPath(), e.Msg)
+}
+
+// PluginError indicates an external plugin command failed to run or exited
+// with a non-zero status
+type PluginError struct {
+	Name     string
+	Path     string
+	ExitCode int // Exit status of the plugin, or -1 if it could not be run
+	Err      error
+	Cmd      *Command
+}
+
+func (e *PluginError) Error() string {
+	return fmt.Sprintf("plugin '%s' for '%s' failed: %v", e.Name, e.Cmd.getCommandPath(), e.Err)
+}
+
+func (e *PluginError) Unwrap() error {
+	return e.Err
+}
+
+// RegistryError indicates a command registered with Register could not be
+// added to the tree
+type RegistryError struct {
+	Path string
+	Msg  string
+}
+
+func (e *RegistryError) Error() string {
+	return fmt.Sprintf("cannot register command '%s': %s", e.Path, e.Msg)
+}
+
+// SpecError indicates a problem in a declarative command spec
+type SpecError struct {
+	Path string // Command path in the spec ("" for the document itself)
+	Msg  string
+}
+
+func (e *SpecError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("invalid spec: %s", e.Msg)
+	}
+	return fmt.Sprintf("invalid spec for '%s': %s", e.Path, e.Msg)
+}
+
+// LineError indicates a command line that can't be split into arguments,
+// such as an unterminated quote
+type LineError struct {
+	Line   string
+	Column int // 1-based position (in characters) where the problem starts
+	Msg    string
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("%s at column %d", e.Msg, e.Column)
+}
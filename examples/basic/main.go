@@ -53,7 +53,7 @@ var rootCmd = cli.Root("basic").
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		cli.PrintError(err)
 		os.Exit(1)
 	}
 }
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
@@ -15,8 +16,13 @@ func (c *Command) Execute() error {
 
 // ExecuteContext runs the command with a context
 func (c *Command) ExecuteContext(ctx context.Context) error {
-	// Use os.Args[1:] (skip program name)
-	args := os.Args[1:]
+	// Registered commands can also be selected by the program name
+	if err := c.mountRegistry(); err != nil {
+		return err
+	}
+
+	// Use os.Args[1:] (skip program name), unless the name selects a subcommand
+	args := c.multiCallArgs(os.Args[0], os.Args[1:])
 	return c.execute(ctx, args)
 }
 
@@ -27,6 +33,13 @@ func (c *Command) ExecuteWithArgs(args []string) error {
 
 // execute is the internal execution logic
 func (c *Command) execute(ctx context.Context, args []string) error {
+	// Assemble commands registered by packages into the root tree
+	if err := c.mountRegistry(); err != nil {
+		return err
+	}
+
+	c.warnDeprecated()
+
 	// First, find if there's a subcommand in the args (look at non-flag args only)
 	subcommandIndex := -1
 	var subcmd *Command
@@ -37,25 +50,65 @@ func (c *Command) execute(ctx context.Context, args []string) error {
 			continue
 		}
 
-		// Check if this is a known subcommand
-		if cmd, exists := c.subcommands[arg]; exists {
+		// The built-in help command shows help for the rest of the line
+		if arg == c.helpFlag && c.hasHelpCommand() {
+			return c.runHelpCommand(args[i+1:])
+		}
+
+		// Check if this is a known subcommand (by name, alias or prefix)
+		cmd, err := c.findSubcommand(arg)
+		if err != nil {
+			return err
+		}
+		if cmd != nil && !cmd.gateOpen(args) {
+			return &CommandNotFoundError{
+				Name: arg,
+				Cmd:  c,
+				Hint: cmd.gateHint(),
+			}
+		}
+		if cmd != nil {
 			subcommandIndex = i
 			subcmd = cmd
 			break
 		}
 
+		// Run an external plugin for an unknown subcommand
+		if !c.acceptsArgs() {
+			if plugin := c.findPlugin(arg); plugin != nil {
+				if err := c.parseParentFlags(args[:i]); err != nil {
+					return err
+				}
+				return c.runPlugin(ctx, plugin, args[i+1:])
+			}
+		}
+
 		// If it's not a flag and not a subcommand:
 		// - If we have subcommands defined BUT no arguments, this is an unknown command error
 		// - Otherwise, it's an argument - stop looking for subcommands
-		if len(c.subcommands) > 0 && len(c.args) == 0 {
-			return &CommandNotFoundError{
-				Name: arg,
-				Cmd:  c,
-			}
+		if len(c.subcommands) > 0 && !c.acceptsArgs() {
+			return c.commandNotFound(arg)
 		}
 		break
 	}
 
+	// Check for the version flag on the root, also before any parsing. Only
+	// the root's own args count: a subcommand may define --version itself.
+	if c.hasVersionFlag() {
+		rootArgs := args
+		if subcommandIndex >= 0 {
+			rootArgs = args[:subcommandIndex]
+		}
+		for _, arg := range rootArgs {
+			if arg == "--" {
+				break
+			}
+			if arg == "--version" {
+				return c.printVersion("text")
+			}
+		}
+	}
+
 	// Check for help flag FIRST - before any parsing
 	if c.helpEnabled {
 		for _, arg := range args {
@@ -64,7 +117,7 @@ func (c *Command) execute(ctx context.Context, args []string) error {
 				targetCmd := c
 				for _, a := range args {
 					if !strings.HasPrefix(a, "-") {
-						if cmd, exists := targetCmd.subcommands[a]; exists {
+						if cmd, _ := targetCmd.findSubcommand(a); cmd != nil && cmd.gateOpen(args) {
 							targetCmd = cmd
 						} else {
 							break
@@ -77,63 +130,45 @@ func (c *Command) execute(ctx context.Context, args []string) error {
 		}
 	}
 
+	// Without a subcommand or positional arguments, run the default subcommand
+	if subcommandIndex < 0 && c.defaultCommand != "" && !hasPositionalArgs(args) {
+		if def := c.GetDefaultCommand(); def != nil {
+			return def.execute(ctx, args)
+		}
+	}
+
 	// If we found a subcommand, delegate to it with remaining args
 	if subcommandIndex >= 0 {
 		// Collect args before and after subcommand
 		beforeSubcmd := args[:subcommandIndex]
 		afterSubcmd := args[subcommandIndex+1:]
 
-		// Get all flags for current command (including inherited)
-		allFlags := c.getAllFlags()
-
 		// Parse flags from BEFORE subcommand only (those belong to parent)
-		if len(beforeSubcmd) > 0 {
-			tempFS := NewFlagSet()
-			for _, flag := range allFlags {
-				tempFS.flags = append(tempFS.flags, flag)
-			}
-
-			remaining, err := tempFS.Parse(beforeSubcmd)
-			if err != nil {
-				return &FlagError{
-					Flag: "",
-					Msg:  err.Error(),
-					Cmd:  c,
-				}
-			}
-
-			// If there are remaining non-flag args before subcommand, that's an error
-			if len(remaining) > 0 {
-				return &CommandNotFoundError{
-					Name: remaining[0],
-					Cmd:  c,
-				}
-			}
+		if err := c.parseParentFlags(beforeSubcmd); err != nil {
+			return err
 		}
 
 		// Execute subcommand with args after the subcommand name
 		return subcmd.execute(ctx, afterSubcmd)
 	}
 
-	// No subcommand found, parse all flags and execute this command
-	allFlags := c.getAllFlags()
-	tempFS := NewFlagSet()
-	for _, flag := range allFlags {
-		tempFS.flags = append(tempFS.flags, flag)
+	// Refuse to run a command whose action or arguments are misdefined
+	if err := c.checkDefinition(); err != nil {
+		return err
 	}
 
-	remaining, err := tempFS.Parse(args)
+	// No subcommand found, parse all flags and execute this command
+	flagSet := c.inheritedFlags()
+	allFlags := flagSet.GetFlags()
+
+	remaining, err := flagSet.Parse(args)
 	if err != nil {
-		return &FlagError{
-			Flag: "",
-			Msg:  err.Error(),
-			Cmd:  c,
-		}
+		return c.flagParseError(err)
 	}
 
-	// Validate required flags
+	// Validate required flags (built-in commands run without them, like --version)
 	for _, flag := range allFlags {
-		if flag.IsRequired() && !flag.IsSet() {
+		if flag.IsRequired() && !flag.IsSet() && !c.builtin {
 			return &FlagError{
 				Flag: flag.names[0],
 				Msg:  "required flag not set",
@@ -142,31 +177,181 @@ func (c *Command) execute(ctx context.Context, args []string) error {
 		}
 	}
 
-	// Validate argument count
-	nonFlagArgs := remaining
-	expectedArgs := len(c.args)
+	// Validate positional arguments before any hook runs
+	remaining, err = c.validateArgs(remaining)
+	if err != nil {
+		return err
+	}
 
-	// Check if action is variadic
-	isVariadic := false
-	if c.action != nil {
-		actionValue := reflect.ValueOf(c.action)
-		actionType := actionValue.Type()
-		isVariadic = actionType.IsVariadic()
+	// Convert arguments to the action's parameter types, still before hooks
+	callArgs, err := c.prepareAction(remaining)
+	if err != nil {
+		return err
+	}
+
+	// Fill struct fields bound with Args
+	if err := c.bindArgFields(remaining); err != nil {
+		return err
 	}
 
-	// Check if we have too many arguments (skip check for variadic)
-	if !isVariadic && len(nonFlagArgs) > expectedArgs {
+	// Check path arguments and flags, and open inputs
+	if err := c.resolveFiles(callArgs, allFlags); err != nil {
+		return err
+	}
+
+	// Execute this command's action
+	return c.executeAction(ctx, remaining, callArgs)
+}
+
+// parseParentFlags parses the flags given before a subcommand, which
+// belong to c. Non-flag arguments there are reported as unknown commands.
+func (c *Command) parseParentFlags(args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	remaining, err := c.inheritedFlags().Parse(args)
+	if err != nil {
+		return c.flagParseError(err)
+	}
+	if len(remaining) > 0 {
+		return c.commandNotFound(remaining[0])
+	}
+	return nil
+}
+
+// hasPositionalArgs reports whether args contain anything but flags
+func hasPositionalArgs(args []string) bool {
+	for _, arg := range args {
+		if arg == "-" || !strings.HasPrefix(arg, "-") {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsArgs reports whether the command takes positional arguments, which
+// decides if an unknown word is an argument or a mistyped subcommand
+func (c *Command) acceptsArgs() bool {
+	return len(c.args) > 0 || len(c.argValidators) > 0
+}
+
+// validateArgs applies the command's argument policy and returns the
+// arguments with defaults filled in. Without explicit validators, the
+// declared args (or a variadic argument or action) bound the count.
+func (c *Command) validateArgs(args []string) ([]string, error) {
+	if len(c.argValidators) > 0 {
+		for _, validate := range c.argValidators {
+			if err := validate(c, args); err != nil {
+				return nil, c.wrapArgumentError(err)
+			}
+		}
+	} else {
+		// Check if action is variadic
+		isVariadic := c.variadicArgIndex() >= 0
+		if c.invoker != nil {
+			isVariadic = isVariadic || c.invoker.variadic != nil
+		}
+
+		// Check if we have too many arguments (skip check for variadic)
+		expectedArgs := len(c.args)
+		if !isVariadic && len(args) > expectedArgs {
+			return nil, &ArgumentError{
+				Arg:   "",
+				Msg:   fmt.Sprintf("too many arguments: expected %d, got %d", expectedArgs, len(args)),
+				Cmd:   c,
+				Usage: c.usageLine(),
+			}
+		}
+	}
+
+	// Check that every required argument is present
+	for i := len(args); i < len(c.args); i++ {
+		if c.args[i].Required {
+			return nil, &ArgumentError{
+				Arg:   c.args[i].Name,
+				Msg:   "required argument missing",
+				Cmd:   c,
+				Usage: c.usageLine(),
+			}
+		}
+	}
+
+	// Fill in defaults for trailing omitted arguments
+	resolved := args
+	for i := len(args); i < len(c.args) && c.args[i].Default != ""; i++ {
+		if len(resolved) == len(args) {
+			resolved = append([]string{}, args...)
+		}
+		resolved = append(resolved, c.args[i].Default)
+	}
+
+	// Check choices and type hints
+	for i, value := range resolved {
+		arg := c.argAt(i)
+		if arg == nil {
+			continue
+		}
+		if err := arg.validate(value); err != nil {
+			return nil, &ArgumentError{
+				Arg:   arg.Name,
+				Msg:   err.Error(),
+				Cmd:   c,
+				Usage: c.usageLine(),
+			}
+		}
+	}
+
+	c.rawArgs = resolved
+	return resolved, nil
+}
+
+// flagParseError converts a FlagSet.Parse error into a FlagError, with
+// suggestions for an unknown flag
+func (c *Command) flagParseError(err error) error {
+	var unknown *unknownFlagError
+	if errors.As(err, &unknown) {
+		return &FlagError{
+			Flag:        unknown.name,
+			Msg:         "unknown flag",
+			Cmd:         c,
+			Suggestions: c.suggestFlags(unknown.name),
+		}
+	}
+	return &FlagError{
+		Flag: "",
+		Msg:  err.Error(),
+		Cmd:  c,
+	}
+}
+
+// wrapArgumentError converts a validator error into an ArgumentError carrying
+// the command's usage line. CommandNotFoundError is passed through unchanged.
+func (c *Command) wrapArgumentError(err error) error {
+	var notFound *CommandNotFoundError
+	if errors.As(err, &notFound) {
+		return err
+	}
+
+	var argErr *ArgumentError
+	if !errors.As(err, &argErr) {
 		return &ArgumentError{
-			Arg: "",
-			Msg: fmt.Sprintf("too many arguments: expected %d, got %d", expectedArgs, len(nonFlagArgs)),
-			Cmd: c,
+			Msg:   err.Error(),
+			Cmd:   c,
+			Usage: c.usageLine(),
 		}
 	}
+	if argErr.Cmd == nil {
+		argErr.Cmd = c
+	}
+	if argErr.Usage == "" {
+		argErr.Usage = c.usageLine()
+	}
+	return err
+}
 
-	// Execute this command's action
-	return c.executeAction(ctx, remaining)
-} // executeAction executes the command's action with lifecycle hooks
-func (c *Command) executeAction(ctx context.Context, args []string) error {
+// executeAction executes the command's action with lifecycle hooks.
+// callArgs are the converted positional parameters from prepareAction.
+func (c *Command) executeAction(ctx context.Context, args []string, callArgs []reflect.Value) error {
 	// Run PersistentPreRun hooks (from root to current)
 	var ancestors []*Command
 	current := c
@@ -180,6 +365,7 @@ func (c *Command) executeAction(ctx context.Context, args []string) error {
 			if err := cmd.persistentPreRun(ctx, c); err != nil {
 				// Still run post hooks on error
 				c.runPostHooks(ctx)
+				c.closeFiles()
 				return err
 			}
 		}
@@ -190,6 +376,7 @@ func (c *Command) executeAction(ctx context.Context, args []string) error {
 		if err := c.preRun(ctx, c); err != nil {
 			// Run post hooks even on PreRun error
 			c.runPostHooks(ctx)
+			c.closeFiles()
 			return err
 		}
 	}
@@ -197,12 +384,15 @@ func (c *Command) executeAction(ctx context.Context, args []string) error {
 	// Execute action
 	var actionErr error
 	if c.action != nil {
-		actionErr = c.callAction(ctx, args)
+		actionErr = c.invoker.call(ctx, c, args, callArgs)
 	}
 
 	// Always run post hooks (even if action failed)
 	c.runPostHooks(ctx)
 
+	// Close inputs opened during validation
+	c.closeFiles()
+
 	return actionErr
 }
 
@@ -223,137 +413,40 @@ func (c *Command) runPostHooks(ctx context.Context) {
 	}
 }
 
-// callAction invokes the action function with proper arguments
-func (c *Command) callAction(ctx context.Context, args []string) error {
-	actionValue := reflect.ValueOf(c.action)
-	actionType := actionValue.Type()
-
-	// Build argument list
-	callArgs := []reflect.Value{
-		reflect.ValueOf(ctx),
-		reflect.ValueOf(c),
-	}
-
-	// Check if function is variadic
-	isVariadic := actionType.IsVariadic()
-	numParams := actionType.NumIn() - 2 // Subtract ctx and cmd
-
-	if isVariadic {
-		// For variadic functions, handle specially
-		// Add non-variadic arguments first (all params except the last variadic one)
-		numFixed := numParams - 1
-
-		for i := 0; i < numFixed; i++ {
-			if i >= len(args) {
-				// Check if this argument is required
-				if i < len(c.args) && c.args[i].Required {
-					return &ArgumentError{
-						Arg: c.args[i].Name,
-						Msg: "required argument missing",
-						Cmd: c,
-					}
-				}
-				// Use zero value for optional arguments
-				callArgs = append(callArgs, reflect.Zero(actionType.In(i+2)))
-				continue
-			}
-
-			// Convert string argument to expected type
-			argType := actionType.In(i + 2)
-			argValue, err := convertArgument(args[i], argType)
-			if err != nil {
-				argName := ""
-				if i < len(c.args) {
-					argName = c.args[i].Name
-				}
-				return &ArgumentError{
-					Arg: argName,
-					Msg: err.Error(),
-					Cmd: c,
-				}
-			}
-
-			callArgs = append(callArgs, argValue)
-		}
-
-		// Get the element type of the variadic parameter
-		sliceType := actionType.In(actionType.NumIn() - 1)
-		elemType := sliceType.Elem()
-
-		// Build a slice for variadic parameters
-		variadicCount := len(args) - numFixed
-		variadicSlice := reflect.MakeSlice(sliceType, variadicCount, variadicCount)
-
-		for i := 0; i < variadicCount; i++ {
-			argValue, err := convertArgument(args[numFixed+i], elemType)
-			if err != nil {
-				return &ArgumentError{
-					Arg: "",
-					Msg: err.Error(),
-					Cmd: c,
-				}
-			}
-			variadicSlice.Index(i).Set(argValue)
-		}
-
-		// Append the slice as a single argument
-		callArgs = append(callArgs, variadicSlice)
-	} else {
-		// Non-variadic function
-		for i := 0; i < numParams; i++ {
-			if i >= len(args) {
-				// Check if this argument is required
-				if i < len(c.args) && c.args[i].Required {
-					return &ArgumentError{
-						Arg: c.args[i].Name,
-						Msg: "required argument missing",
-						Cmd: c,
-					}
-				}
-				// Use zero value for optional arguments
-				callArgs = append(callArgs, reflect.Zero(actionType.In(i+2)))
-				continue
-			}
-
-			// Convert string argument to expected type
-			argType := actionType.In(i + 2)
-			argValue, err := convertArgument(args[i], argType)
-			if err != nil {
-				argName := ""
-				if i < len(c.args) {
-					argName = c.args[i].Name
-				}
-				return &ArgumentError{
-					Arg: argName,
-					Msg: err.Error(),
-					Cmd: c,
-				}
-			}
-
-			callArgs = append(callArgs, argValue)
-		}
+// prepareAction converts positional arguments to the action's parameter
+// types. Missing optional arguments become zero values. It runs during
+// validation so conversion failures are reported before any hook.
+func (c *Command) prepareAction(args []string) ([]reflect.Value, error) {
+	if c.invoker == nil {
+		return nil, nil
 	}
+	return c.invoker.prepare(c, args)
+}
 
-	// Call the action function
-	var results []reflect.Value
-	if isVariadic {
-		// For variadic functions, use CallSlice with the slice as the last argument
-		// CallSlice will expand the slice elements into individual variadic parameters
-		results = actionValue.CallSlice(callArgs)
-	} else {
-		results = actionValue.Call(callArgs)
+// conversionError builds the ArgumentError for a failed conversion of the
+// argument at position i
+func (c *Command) conversionError(i int, err error) error {
+	argName := ""
+	if arg := c.argAt(i); arg != nil {
+		argName = arg.Name
 	}
-
-	// Check if there's an error return value
-	if len(results) > 0 && !results[0].IsNil() {
-		return results[0].Interface().(error)
+	return &ArgumentError{
+		Arg:   argName,
+		Msg:   err.Error(),
+		Cmd:   c,
+		Usage: c.usageLine(),
 	}
-
-	return nil
 }
 
-// convertArgument converts a string argument to the target type
+// convertArgument converts a string argument to the target type.
+// Pointer targets are allocated and the value is converted into the element.
 func convertArgument(arg string, targetType reflect.Type) (reflect.Value, error) {
+	return converterFor(targetType)(arg)
+}
+
+// parseWithFlagSet converts a string argument using the flag value parser,
+// which also handles custom types with a Set(string) error method
+func parseWithFlagSet(arg string, targetType reflect.Type) (reflect.Value, error) {
 	// Use the same conversion logic as flag parsing
 	tempFS := NewFlagSet()
 
@@ -350,3 +350,56 @@ func TestExecuteFlagsAfterSubcommand(t *testing.T) {
 		}
 	}
 }
+
+// TestExecutePointerArgs tests that pointer parameters distinguish absent arguments from zero values
+func TestExecutePointerArgs(t *testing.T) {
+	var count *int
+	var name *string
+	called := false
+
+	cmd := Root("test").
+		Arg("count", "Count", false).
+		Arg("name", "Name", false).
+		Action(func(ctx context.Context, c *Command, n *int, s *string) error {
+			count, name, called = n, s, true
+			return nil
+		})
+
+	if err := cmd.ExecuteWithArgs([]string{}); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if !called || count != nil || name != nil {
+		t.Errorf("expected nil pointers for absent arguments, got %v %v", count, name)
+	}
+
+	if err := cmd.ExecuteWithArgs([]string{"0"}); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if count == nil || *count != 0 {
+		t.Errorf("expected pointer to 0, got %v", count)
+	}
+	if name != nil {
+		t.Errorf("expected nil name, got %q", *name)
+	}
+
+	if err := cmd.ExecuteWithArgs([]string{"nope"}); err == nil {
+		t.Error("expected conversion error for pointer argument")
+	}
+}
+
+// TestExecutePointerVariadicArgs tests pointer element types in variadic parameters
+func TestExecutePointerVariadicArgs(t *testing.T) {
+	var received []*int
+	cmd := Root("test").
+		Action(func(ctx context.Context, c *Command, values ...*int) error {
+			received = values
+			return nil
+		})
+
+	if err := cmd.ExecuteWithArgs([]string{"1", "2"}); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if len(received) != 2 || *received[0] != 1 || *received[1] != 2 {
+		t.Errorf("unexpected values: %v", received)
+	}
+}
@@ -0,0 +1,294 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// ExistingFile is a path argument or flag that must name an existing regular file
+type ExistingFile string
+
+// ExistingDir is a path argument or flag that must name an existing directory
+type ExistingDir string
+
+// WritablePath is a path argument or flag that can be created or overwritten:
+// its parent directory must exist and the path itself must not be a directory.
+// An existing file must be writable; this is only checked on the OS file
+// system, as fs.FS cannot report it.
+type WritablePath string
+
+// Input is an argument or flag naming a file to read. The framework opens it
+// before the hooks run and closes it after the action; "-" reads from stdin.
+// Action parameters of type io.Reader are bound the same way.
+type Input struct {
+	Name string
+
+	reader io.Reader
+	closer io.Closer
+}
+
+// Read reads from the opened file or stdin
+func (in *Input) Read(p []byte) (int, error) {
+	if in.reader == nil {
+		return 0, fmt.Errorf("input %q is not open", in.Name)
+	}
+	return in.reader.Read(p)
+}
+
+// Set records the file name (opening is deferred until validation)
+func (in *Input) Set(value string) error {
+	in.Name = value
+	return nil
+}
+
+// String returns the file name
+func (in *Input) String() string {
+	return in.Name
+}
+
+// Completion directives emitted in place of words when the next argument is
+// a path; the generated shell scripts replace them with native completion
+const (
+	completeFilesDirective = ":files"
+	completeDirsDirective  = ":dirs"
+)
+
+var (
+	readerType       = reflect.TypeOf((*io.Reader)(nil)).Elem()
+	inputType        = reflect.TypeOf(Input{})
+	existingFileType = reflect.TypeOf(ExistingFile(""))
+	existingDirType  = reflect.TypeOf(ExistingDir(""))
+	writablePathType = reflect.TypeOf(WritablePath(""))
+)
+
+// osFS is the default file system, backed by the os package. Unlike
+// os.DirFS it accepts absolute and relative paths as typed by the user.
+type osFS struct{}
+
+func (osFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (osFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// SetFileSystem sets the file system used to check and open path arguments
+// and flags for this command and its subcommands (default: the OS file system)
+func (c *Command) SetFileSystem(fsys fs.FS) *Command {
+	c.fsys = fsys
+	return c
+}
+
+// SetStdin sets the reader used for "-" inputs for this command and its
+// subcommands (default: os.Stdin)
+func (c *Command) SetStdin(r io.Reader) *Command {
+	c.stdin = r
+	return c
+}
+
+// fileSystem returns the nearest file system set on this command or an ancestor
+func (c *Command) fileSystem() fs.FS {
+	for current := c; current != nil; current = current.parent {
+		if current.fsys != nil {
+			return current.fsys
+		}
+	}
+	return osFS{}
+}
+
+// stdinReader returns the nearest stdin set on this command or an ancestor
+func (c *Command) stdinReader() io.Reader {
+	for current := c; current != nil; current = current.parent {
+		if current.stdin != nil {
+			return current.stdin
+		}
+	}
+	return os.Stdin
+}
+
+// argPathHint returns "file" or "dir" if the argument at position i is a
+// path, from its Type hint or the action parameter type, and "" otherwise
+func (c *Command) argPathHint(i int) string {
+	if arg := c.argAt(i); arg != nil && (arg.Type == "file" || arg.Type == "dir") {
+		return arg.Type
+	}
+
+	return pathTypeHint(c.actionParamType(i))
+}
+
+// pathTypeHint returns "file" or "dir" for path types (and pointers or
+// slices of them), and "" otherwise
+func pathTypeHint(t reflect.Type) string {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	switch t {
+	case existingFileType, writablePathType, inputType, readerType:
+		return "file"
+	case existingDirType:
+		return "dir"
+	}
+	return ""
+}
+
+// resolveFiles checks path values and opens inputs found in the prepared
+// action arguments and the command's flags. On error, files opened so far
+// are closed again.
+func (c *Command) resolveFiles(callArgs []reflect.Value, flags []*Flag) error {
+	for i, value := range callArgs {
+		if err := c.resolveFileValue(value); err != nil {
+			c.closeFiles()
+			return c.conversionError(i, err)
+		}
+	}
+
+	for _, field := range c.argFields {
+		if err := c.resolveFileValue(field.value); err != nil {
+			c.closeFiles()
+			return c.conversionError(field.index, err)
+		}
+	}
+
+	for _, flag := range flags {
+		if err := c.resolveFileValue(flag.value); err != nil {
+			c.closeFiles()
+			return &FlagError{
+				Flag: flag.PrimaryName(),
+				Msg:  err.Error(),
+				Cmd:  c,
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveFileValue validates or opens a single value, descending into
+// pointers, interfaces and slices
+func (c *Command) resolveFileValue(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return c.resolveFileValue(v.Elem())
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := c.resolveFileValue(v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch v.Type() {
+	case existingFileType:
+		return checkExistingFile(c.fileSystem(), v.String())
+	case existingDirType:
+		return checkExistingDir(c.fileSystem(), v.String())
+	case writablePathType:
+		return checkWritablePath(c.fileSystem(), v.String())
+	case inputType:
+		if !v.CanAddr() {
+			return nil
+		}
+		return c.openInput(v.Addr().Interface().(*Input))
+	}
+	return nil
+}
+
+// openInput opens the named input, or binds stdin for "-"
+func (c *Command) openInput(in *Input) error {
+	if in.Name == "" || in.reader != nil {
+		return nil
+	}
+	if in.Name == "-" {
+		// Recorded so the next invocation binds stdin again; it is not closed
+		in.reader = c.stdinReader()
+		c.openedFiles = append(c.openedFiles, in)
+		return nil
+	}
+
+	file, err := c.fileSystem().Open(in.Name)
+	if err != nil {
+		return err
+	}
+	in.reader = file
+	in.closer = file
+	c.openedFiles = append(c.openedFiles, in)
+	return nil
+}
+
+// closeFiles closes all inputs opened for the current invocation and
+// releases stdin inputs without closing stdin
+func (c *Command) closeFiles() {
+	for _, in := range c.openedFiles {
+		if in.closer != nil {
+			in.closer.Close()
+		}
+		in.reader = nil
+		in.closer = nil
+	}
+	c.openedFiles = nil
+}
+
+func checkExistingFile(fsys fs.FS, name string) error {
+	if name == "" {
+		return nil
+	}
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return fmt.Errorf("file %q does not exist", name)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%q is a directory, not a file", name)
+	}
+	return nil
+}
+
+func checkExistingDir(fsys fs.FS, name string) error {
+	if name == "" {
+		return nil
+	}
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return fmt.Errorf("directory %q does not exist", name)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%q is not a directory", name)
+	}
+	return nil
+}
+
+func checkWritablePath(fsys fs.FS, name string) error {
+	if name == "" {
+		return nil
+	}
+	if info, err := fs.Stat(fsys, name); err == nil {
+		if info.IsDir() {
+			return fmt.Errorf("%q is a directory", name)
+		}
+		// fs.FS cannot tell whether a file is writable; on the OS file
+		// system, the real access is tested by opening the file for writing
+		if _, ok := fsys.(osFS); ok {
+			file, err := os.OpenFile(name, os.O_WRONLY, 0)
+			if err != nil {
+				return fmt.Errorf("%q is not writable", name)
+			}
+			file.Close()
+		}
+		return nil
+	}
+
+	parent := filepath.Dir(name)
+	info, err := fs.Stat(fsys, parent)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("directory %q does not exist", parent)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"data/input.txt": {Data: []byte("hello"), Mode: 0644},
+	}
+}
+
+// TestPathArgumentTypes tests validation of file, directory and writable path arguments
+func TestPathArgumentTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		action  interface{}
+		args    []string
+		wantErr bool
+	}{
+		{"existing file", func(ctx context.Context, c *Command, f ExistingFile) error { return nil }, []string{"data/input.txt"}, false},
+		{"missing file", func(ctx context.Context, c *Command, f ExistingFile) error { return nil }, []string{"data/missing.txt"}, true},
+		{"file is a directory", func(ctx context.Context, c *Command, f ExistingFile) error { return nil }, []string{"data"}, true},
+		{"existing dir", func(ctx context.Context, c *Command, d ExistingDir) error { return nil }, []string{"data"}, false},
+		{"dir is a file", func(ctx context.Context, c *Command, d ExistingDir) error { return nil }, []string{"data/input.txt"}, true},
+		{"writable new path", func(ctx context.Context, c *Command, p WritablePath) error { return nil }, []string{"data/out.txt"}, false},
+		{"writable missing parent", func(ctx context.Context, c *Command, p WritablePath) error { return nil }, []string{"nope/out.txt"}, true},
+		{"writable existing file", func(ctx context.Context, c *Command, p WritablePath) error { return nil }, []string{"data/input.txt"}, false},
+		{"writable path is a directory", func(ctx context.Context, c *Command, p WritablePath) error { return nil }, []string{"data"}, true},
+		{"variadic files", func(ctx context.Context, c *Command, f ...ExistingFile) error { return nil }, []string{"data/input.txt", "data/missing.txt"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preRan := false
+			cmd := Root("app").
+				SetFileSystem(testFS()).
+				Arg("path", "Path", true).
+				PreRun(func(ctx context.Context, c *Command) error {
+					preRan = true
+					return nil
+				}).
+				Action(tt.action)
+
+			err := cmd.ExecuteWithArgs(tt.args)
+			if tt.wantErr {
+				if _, ok := err.(*ArgumentError); !ok {
+					t.Fatalf("expected ArgumentError, got %T (%v)", err, err)
+				}
+				if preRan {
+					t.Error("PreRun should not run when a path is invalid")
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestInputArgument tests io.Reader and Input arguments, including stdin
+func TestInputArgument(t *testing.T) {
+	t.Run("io.Reader from file", func(t *testing.T) {
+		var content string
+		var opened *Command
+		cmd := Root("app").
+			SetFileSystem(testFS()).
+			Arg("input", "Input file", true).
+			Action(func(ctx context.Context, c *Command, r io.Reader) error {
+				data, err := io.ReadAll(r)
+				content = string(data)
+				opened = c
+				return err
+			})
+
+		if err := cmd.ExecuteWithArgs([]string{"data/input.txt"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if content != "hello" {
+			t.Errorf("expected 'hello', got %q", content)
+		}
+		if len(opened.openedFiles) != 0 {
+			t.Error("inputs should be closed after the action")
+		}
+	})
+
+	t.Run("dash reads stdin", func(t *testing.T) {
+		var content string
+		cmd := Root("app").
+			SetStdin(strings.NewReader("from stdin")).
+			Arg("input", "Input file", true).
+			Action(func(ctx context.Context, c *Command, in *Input) error {
+				data, err := io.ReadAll(in)
+				content = string(data)
+				return err
+			})
+
+		if err := cmd.ExecuteWithArgs([]string{"-"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if content != "from stdin" {
+			t.Errorf("expected 'from stdin', got %q", content)
+		}
+	})
+
+	t.Run("stdin is bound again on each run", func(t *testing.T) {
+		var in Input
+		var content string
+		cmd := Root("app").
+			Flag(&in, "input", "i", nil, "Input").
+			Action(func(ctx context.Context, c *Command) error {
+				data, err := io.ReadAll(&in)
+				content = string(data)
+				return err
+			})
+
+		for _, text := range []string{"first", "second"} {
+			cmd.SetStdin(strings.NewReader(text))
+			if err := cmd.ExecuteWithArgs([]string{"--input=-"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if content != text {
+				t.Errorf("expected %q, got %q", text, content)
+			}
+		}
+		if len(cmd.openedFiles) != 0 {
+			t.Error("stdin inputs should be released after the action")
+		}
+	})
+
+	t.Run("missing input fails before hooks", func(t *testing.T) {
+		cmd := Root("app").
+			SetFileSystem(testFS()).
+			Arg("input", "Input file", true).
+			PreRun(func(ctx context.Context, c *Command) error {
+				t.Error("PreRun should not run")
+				return nil
+			}).
+			Action(func(ctx context.Context, c *Command, r io.Reader) error {
+				return nil
+			})
+
+		err := cmd.ExecuteWithArgs([]string{"data/missing.txt"})
+		if _, ok := err.(*ArgumentError); !ok {
+			t.Errorf("expected ArgumentError, got %T", err)
+		}
+	})
+}
+
+// TestWritablePathOS tests that writability is checked on the OS file system
+func TestWritablePathOS(t *testing.T) {
+	dir := t.TempDir()
+	writable := filepath.Join(dir, "out.txt")
+	readonly := filepath.Join(dir, "readonly.txt")
+	if err := os.WriteFile(writable, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(readonly, nil, 0444); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkWritablePath(osFS{}, writable); err != nil {
+		t.Errorf("expected writable file to pass, got %v", err)
+	}
+	if err := checkWritablePath(osFS{}, filepath.Join(dir, "new.txt")); err != nil {
+		t.Errorf("expected new path to pass, got %v", err)
+	}
+
+	// Permissions do not restrict root
+	if os.Geteuid() == 0 {
+		t.Skip("running as root")
+	}
+	if err := checkWritablePath(osFS{}, readonly); err == nil {
+		t.Error("expected read-only file to be rejected")
+	}
+}
+
+// TestPathFlagTypes tests path types used as flags
+func TestPathFlagTypes(t *testing.T) {
+	var config ExistingFile
+	var input Input
+	var content string
+
+	root := Root("app").
+		SetFileSystem(testFS()).
+		Flag(&config, "config", "c", "", "Config file")
+	child := Cmd("read").
+		Flag(&input, "input", "i", nil, "Input file").
+		Action(func(ctx context.Context, c *Command) error {
+			data, err := io.ReadAll(&input)
+			content = string(data)
+			return err
+		})
+	root.AddCommand(child)
+
+	if err := root.ExecuteWithArgs([]string{"read", "--config=data/input.txt", "--input=data/input.txt"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content != "hello" {
+		t.Errorf("expected 'hello', got %q", content)
+	}
+
+	err := root.ExecuteWithArgs([]string{"read", "--config=data/missing.txt"})
+	flagErr, ok := err.(*FlagError)
+	if !ok {
+		t.Fatalf("expected FlagError, got %T", err)
+	}
+	if flagErr.Flag != "config" {
+		t.Errorf("expected error for 'config', got %q", flagErr.Flag)
+	}
+}
+
+// TestPathArgumentCompletion tests the file and directory completion directives
+func TestPathArgumentCompletion(t *testing.T) {
+	root := Root("app")
+	cat := Cmd("cat").
+		Action(func(ctx context.Context, c *Command, files ...ExistingFile) error { return nil })
+	cd := Cmd("cd").
+		Action(func(ctx context.Context, c *Command, dir ExistingDir) error { return nil })
+	root.AddCommand(cat)
+	root.AddCommand(cd)
+
+	if words := getCompletionWords(cat, []string{"a"}); !containsWord(words, completeFilesDirective) {
+		t.Errorf("expected file directive, got %v", words)
+	}
+	if words := getCompletionWords(cd, nil); !containsWord(words, completeDirsDirective) {
+		t.Errorf("expected dir directive, got %v", words)
+	}
+	if words := getCompletionWords(root, nil); containsWord(words, completeFilesDirective) {
+		t.Errorf("root should not request file completion, got %v", words)
+	}
+
+	script := (&BashCompletion{}).GenerateScript(root)
+	if !strings.Contains(script, "compgen -f") {
+		t.Error("bash script should fall back to file completion")
+	}
+}
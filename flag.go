@@ -10,7 +10,8 @@ import (
 
 // FlagSet manages command flags
 type FlagSet struct {
-	flags []*Flag // Array storage for flags (pointers to preserve modifications)
+	flags []*Flag          // Array storage for flags (pointers to preserve modifications)
+	index map[string]*Flag // Lookup by every name (first flag registered under a name wins)
 }
 
 // Flag represents a command flag
@@ -23,6 +24,7 @@ type Flag struct {
 	required bool          // Whether flag is required (future)
 	hidden   bool          // Whether to hide from help (future)
 	set      bool          // Whether flag was actually set by user
+	initial  reflect.Value // Value when the flag was added, restored by reset
 }
 
 // Getter methods
@@ -115,10 +117,32 @@ func (f *Flag) setValue(val reflect.Value) {
 	f.value = val
 }
 
+// reset restores the value the flag had when it was added and clears its set state
+func (f *Flag) reset() {
+	if f.initial.IsValid() {
+		f.value.Set(f.initial)
+	}
+	f.set = false
+}
+
 // NewFlagSet creates a new flag set
 func NewFlagSet() *FlagSet {
 	return &FlagSet{
 		flags: []*Flag{},
+		index: make(map[string]*Flag),
+	}
+}
+
+// addFlag appends a flag and indexes all its names
+func (fs *FlagSet) addFlag(flag *Flag) {
+	fs.flags = append(fs.flags, flag)
+	if fs.index == nil {
+		fs.index = make(map[string]*Flag)
+	}
+	for _, name := range flag.names {
+		if _, exists := fs.index[name]; !exists {
+			fs.index[name] = flag
+		}
 	}
 }
 
@@ -153,13 +177,17 @@ func (fs *FlagSet) Add(ptr interface{}, name, shorthand string, defaultValue int
 		value:    flagValue,
 		required: false,
 		hidden:   false,
+		initial:  reflect.ValueOf(flagValue.Interface()),
 	}
 
-	fs.flags = append(fs.flags, &flag)
+	fs.addFlag(&flag)
 }
 
-// Get returns a flag by name
+// GetFlag returns a flag by any of its names
 func (fs *FlagSet) GetFlag(name string) *Flag {
+	if fs.index != nil {
+		return fs.index[name]
+	}
 	for _, flag := range fs.flags {
 		if flag.HasName(name) {
 			return flag
@@ -182,6 +210,15 @@ func (fs *FlagSet) GetAll() []Flag {
 	return result
 }
 
+// unknownFlagError is returned by Parse for a flag not in the set
+type unknownFlagError struct {
+	name string
+}
+
+func (e *unknownFlagError) Error() string {
+	return fmt.Sprintf("unknown flag: %s", e.name)
+}
+
 // Parse parses command line arguments and sets flag values
 func (fs *FlagSet) Parse(args []string) ([]string, error) {
 	remaining := make([]string, 0)
@@ -189,7 +226,8 @@ func (fs *FlagSet) Parse(args []string) ([]string, error) {
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
 
-		if !strings.HasPrefix(arg, "-") {
+		// A lone "-" is a positional argument (conventionally stdin)
+		if arg == "-" || !strings.HasPrefix(arg, "-") {
 			remaining = append(remaining, arg)
 			continue
 		}
@@ -222,7 +260,7 @@ func (fs *FlagSet) Parse(args []string) ([]string, error) {
 
 		flag := fs.GetFlag(flagName)
 		if flag == nil {
-			return nil, fmt.Errorf("unknown flag: %s", flagName)
+			return nil, &unknownFlagError{name: flagName}
 		}
 
 		// Handle boolean flags
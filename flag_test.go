@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"context"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -226,3 +228,106 @@ func TestFlagHasName(t *testing.T) {
 		t.Error("should not have name 'other'")
 	}
 }
+
+// TestFlagSetIndexedLookup tests lookup by every flag name
+func TestFlagSetIndexedLookup(t *testing.T) {
+	var port int
+	var host string
+	fs := NewFlagSet()
+	fs.Add(&port, "port", "p", 8080, "Port")
+	fs.Add(&host, "host", "", "localhost", "Host")
+
+	if fs.GetFlag("port") == nil || fs.GetFlag("p") != fs.GetFlag("port") {
+		t.Error("port should be found by long and short name")
+	}
+	if fs.GetFlag("host") == nil {
+		t.Error("host should be found")
+	}
+	if fs.GetFlag("missing") != nil {
+		t.Error("unknown flag should not be found")
+	}
+
+	// A zero-value FlagSet still works
+	var empty FlagSet
+	if empty.GetFlag("port") != nil {
+		t.Error("empty flag set should not find flags")
+	}
+}
+
+// TestInheritedFlagsCache tests that the inherited flag view is invalidated on mutation
+func TestInheritedFlagsCache(t *testing.T) {
+	var verbose, debug bool
+	var child string
+
+	root := Root("app").Flag(&verbose, "verbose", "v", false, "Verbose")
+	mid := Cmd("mid")
+	leaf := Cmd("leaf").Flag(&child, "name", "n", "", "Name")
+	mid.AddCommand(leaf)
+
+	// Cached before being attached to the root
+	if leaf.inheritedFlags().GetFlag("verbose") != nil {
+		t.Fatal("leaf should not see root flags before being attached")
+	}
+
+	root.AddCommand(mid)
+	if leaf.inheritedFlags().GetFlag("verbose") == nil {
+		t.Error("attaching should invalidate the cached view of descendants")
+	}
+
+	first := leaf.inheritedFlags()
+	if leaf.inheritedFlags() != first {
+		t.Error("view should be cached between calls")
+	}
+
+	root.Flag(&debug, "debug", "d", false, "Debug")
+	if leaf.inheritedFlags().GetFlag("d") == nil {
+		t.Error("adding a root flag should invalidate descendant views")
+	}
+
+	if err := root.ExecuteWithArgs([]string{"mid", "leaf", "-d", "--name=x"}); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if !debug || child != "x" {
+		t.Errorf("expected debug and name to be set, got %v %q", debug, child)
+	}
+}
+
+// TestInheritedFlagsShadowing tests that child flags shadow parent flags in the indexed view
+func TestInheritedFlagsShadowing(t *testing.T) {
+	var parentOut, childOut string
+
+	root := Root("app").Flag(&parentOut, "output", "o", "text", "Parent output")
+	child := Cmd("get").Flag(&childOut, "output", "o", "json", "Child output")
+	root.AddCommand(child)
+
+	if err := root.ExecuteWithArgs([]string{"get", "-o=yaml"}); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if childOut != "yaml" || parentOut != "text" {
+		t.Errorf("child flag should shadow parent flag, got child=%q parent=%q", childOut, parentOut)
+	}
+}
+
+func BenchmarkExecuteLargeTree(b *testing.B) {
+	var verbose bool
+	root := Root("app").Flag(&verbose, "verbose", "v", false, "Verbose")
+	for i := 0; i < 20; i++ {
+		group := Cmd(fmt.Sprintf("group%d", i))
+		for j := 0; j < 20; j++ {
+			var value string
+			cmd := Cmd(fmt.Sprintf("cmd%d", j)).
+				Flag(&value, fmt.Sprintf("value%d", j), "", "", "Value").
+				Action(func(ctx context.Context, c *Command) error { return nil })
+			group.AddCommand(cmd)
+		}
+		root.AddCommand(group)
+	}
+	args := []string{"--verbose", "group19", "cmd19", "--value19=x"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := root.ExecuteWithArgs(args); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CommandGroup is a titled category of subcommands in help output
+type CommandGroup struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// CommandOrder controls the order in which subcommands are listed
+type CommandOrder int
+
+const (
+	// OrderAlphabetical lists subcommands sorted by name (default)
+	OrderAlphabetical CommandOrder = iota
+	// OrderDeclaration lists subcommands in the order they were added
+	OrderDeclaration
+)
+
+// AddGroup declares a titled group for subcommands, e.g.
+// AddGroup("core", "Core Commands"). Groups are shown in declaration order.
+func (c *Command) AddGroup(id, title string) *Command {
+	for i, group := range c.groups {
+		if group.ID == id {
+			c.groups[i].Title = title
+			return c
+		}
+	}
+	c.groups = append(c.groups, CommandGroup{ID: id, Title: title})
+	return c
+}
+
+// GetGroups returns the groups declared for subcommands
+func (c *Command) GetGroups() []CommandGroup {
+	return c.groups
+}
+
+// Group places the command in a group declared on its parent with AddGroup
+func (c *Command) Group(id string) *Command {
+	c.group = id
+	return c
+}
+
+// GetGroup returns the ID of the command's group, or "" if ungrouped
+func (c *Command) GetGroup() string {
+	return c.group
+}
+
+// OrderCommands sets how subcommands are ordered in help, completion and
+// validation throughout the tree. Set it on the root.
+func (c *Command) OrderCommands(order CommandOrder) *Command {
+	c.commandOrder = order
+	return c
+}
+
+// sortedSubcommands returns the subcommands in the order configured on the root
+func (c *Command) sortedSubcommands() []*Command {
+	names := make([]string, 0, len(c.subcommands))
+	for _, name := range c.order {
+		if _, exists := c.subcommands[name]; exists {
+			names = append(names, name)
+		}
+	}
+	// Commands added to the map directly are listed after declared ones
+	if len(names) < len(c.subcommands) {
+		var extra []string
+		for name := range c.subcommands {
+			if !containsString(names, name) {
+				extra = append(extra, name)
+			}
+		}
+		sort.Strings(extra)
+		names = append(names, extra...)
+	}
+
+	if c.root().commandOrder == OrderAlphabetical {
+		sort.Strings(names)
+	}
+
+	cmds := make([]*Command, len(names))
+	for i, name := range names {
+		cmds[i] = c.subcommands[name]
+	}
+	return cmds
+}
+
+// groupedSubcommands returns the visible subcommands split into sections:
+// one per declared group with commands, then ungrouped commands titled
+// "Commands" (or "Additional Commands" when groups are shown)
+func (c *Command) groupedSubcommands() []commandSection {
+	byGroup := make(map[string][]*Command)
+	var ungrouped []*Command
+	for _, cmd := range c.sortedSubcommands() {
+		if !cmd.isListed() {
+			continue
+		}
+		if c.hasGroup(cmd.group) {
+			byGroup[cmd.group] = append(byGroup[cmd.group], cmd)
+		} else {
+			ungrouped = append(ungrouped, cmd)
+		}
+	}
+
+	// The built-in help command is listed with the ungrouped commands
+	if c.hasHelpCommand() {
+		help := Cmd(c.helpFlag).Description("Help about any command or topic")
+		ungrouped = append(ungrouped, help)
+		if c.commandOrder == OrderAlphabetical {
+			sort.SliceStable(ungrouped, func(i, j int) bool { return ungrouped[i].name < ungrouped[j].name })
+		}
+	}
+
+	var sections []commandSection
+	for _, group := range c.groups {
+		if cmds := byGroup[group.ID]; len(cmds) > 0 {
+			sections = append(sections, commandSection{title: group.Title, commands: cmds})
+		}
+	}
+	if len(ungrouped) > 0 {
+		title := "Commands"
+		if len(sections) > 0 {
+			title = "Additional Commands"
+		}
+		sections = append(sections, commandSection{title: title, commands: ungrouped})
+	}
+	return sections
+}
+
+// commandSection is a titled list of subcommands in help output
+type commandSection struct {
+	title    string
+	commands []*Command
+}
+
+// hasGroup reports whether a group with the given ID was declared
+func (c *Command) hasGroup(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, group := range c.groups {
+		if group.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDefaultCommand reports a default subcommand that does not exist
+func (c *Command) checkDefaultCommand() error {
+	if c.defaultCommand == "" || c.GetDefaultCommand() != nil {
+		return nil
+	}
+	return &DefinitionError{
+		Msg: fmt.Sprintf("default command %q is not a subcommand", c.defaultCommand),
+		Cmd: c,
+	}
+}
+
+// checkGroup reports a command placed in a group its parent does not declare
+func (c *Command) checkGroup() error {
+	if c.group == "" || c.parent == nil || c.parent.hasGroup(c.group) {
+		return nil
+	}
+	return &DefinitionError{
+		Msg: fmt.Sprintf("group %q is not declared on '%s'", c.group, c.parent.getCommandPath()),
+		Cmd: c,
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureStdout returns what fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+// indexes returns the position of each word in s, failing if one is missing
+func indexes(t *testing.T, s string, words ...string) []int {
+	t.Helper()
+	var result []int
+	for _, word := range words {
+		i := strings.Index(s, word)
+		if i < 0 {
+			t.Fatalf("%q not found in output:\n%s", word, s)
+		}
+		result = append(result, i)
+	}
+	return result
+}
+
+func isAscending(values []int) bool {
+	for i := 1; i < len(values); i++ {
+		if values[i] <= values[i-1] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestHelpCommandOrdering tests that help lists commands in a stable order
+func TestHelpCommandOrdering(t *testing.T) {
+	root := Root("app")
+	for _, name := range []string{"zeta", "alpha", "mid"} {
+		root.AddCommand(Cmd(name))
+	}
+
+	first := captureStdout(t, root.ShowHelp)
+	if !isAscending(indexes(t, first, "alpha", "mid", "zeta")) {
+		t.Errorf("expected alphabetical order by default:\n%s", first)
+	}
+	for i := 0; i < 10; i++ {
+		if out := captureStdout(t, root.ShowHelp); out != first {
+			t.Fatalf("help output changed between runs:\n%s\n---\n%s", first, out)
+		}
+	}
+
+	root.OrderCommands(OrderDeclaration)
+	out := captureStdout(t, root.ShowHelp)
+	if !isAscending(indexes(t, out, "zeta", "alpha", "mid")) {
+		t.Errorf("expected declaration order:\n%s", out)
+	}
+}
+
+// TestHelpCommandGroups tests titled command groups in help
+func TestHelpCommandGroups(t *testing.T) {
+	root := Root("app").
+		AddGroup("core", "Core").
+		AddGroup("mgmt", "Management").
+		AddGroup("empty", "Unused")
+	root.AddCommand(Cmd("users").Group("mgmt"))
+	root.AddCommand(Cmd("run").Group("core"))
+	root.AddCommand(Cmd("build").Group("core"))
+	root.AddCommand(Cmd("version"))
+	root.AddCommand(Cmd("debug").Group("core").Hidden())
+
+	out := captureStdout(t, root.ShowHelp)
+	if !isAscending(indexes(t, out, "Core", "build", "run", "Management", "users", "Additional Commands", "version")) {
+		t.Errorf("unexpected grouping:\n%s", out)
+	}
+	if strings.Contains(out, "Unused") {
+		t.Errorf("empty groups should not be shown:\n%s", out)
+	}
+	if strings.Contains(out, "debug") {
+		t.Errorf("hidden commands should not be shown:\n%s", out)
+	}
+	if got := root.GetGroups(); len(got) != 3 || got[0].Title != "Core" {
+		t.Errorf("unexpected groups: %v", got)
+	}
+}
+
+// TestCompletionCommandOrdering tests that completion words follow the command order
+func TestCompletionCommandOrdering(t *testing.T) {
+	root := Root("app").OrderCommands(OrderDeclaration)
+	root.AddCommand(Cmd("zeta"))
+	root.AddCommand(Cmd("alpha").Aliases("a"))
+
+	words := getCompletionWords(root, nil)
+	if len(words) < 3 || words[0] != "zeta" || words[1] != "alpha" || words[2] != "a" {
+		t.Errorf("unexpected completion order: %v", words)
+	}
+
+	root.OrderCommands(OrderAlphabetical)
+	words = getCompletionWords(root, nil)
+	if words[0] != "alpha" || words[2] != "zeta" {
+		t.Errorf("unexpected completion order: %v", words)
+	}
+}
+
+// TestValidateUndeclaredGroup tests that Validate reports unknown groups
+func TestValidateUndeclaredGroup(t *testing.T) {
+	root := Root("app").AddGroup("core", "Core")
+	root.AddCommand(Cmd("run").Group("core"))
+	root.AddCommand(Cmd("misc").Group("other"))
+
+	err := root.Validate()
+	var defErr *DefinitionError
+	if !errors.As(err, &defErr) || defErr.Cmd.GetName() != "misc" {
+		t.Fatalf("expected DefinitionError for misc, got %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/nyxstack/color"
+)
+
+// HelpTopic is a help page that is not a command, shown by "help <topic>"
+type HelpTopic struct {
+	Name        string
+	Description string // One-line summary listed in the root help
+	Body        string // Full text of the page
+}
+
+// AddHelpTopic registers a help topic on the root, e.g.
+// AddHelpTopic("environment", "Environment variables", body)
+func (c *Command) AddHelpTopic(name, description, body string) *Command {
+	for i, topic := range c.helpTopics {
+		if topic.Name == name {
+			c.helpTopics[i] = HelpTopic{Name: name, Description: description, Body: body}
+			return c
+		}
+	}
+	c.helpTopics = append(c.helpTopics, HelpTopic{Name: name, Description: description, Body: body})
+	return c
+}
+
+// GetHelpTopics returns the help topics registered on the command
+func (c *Command) GetHelpTopics() []HelpTopic {
+	return c.helpTopics
+}
+
+// hasHelpCommand reports whether the built-in help command is available on
+// c: it is the root, help is enabled, there is something to show help for,
+// and no subcommand uses the name. The command is named after the help flag.
+func (c *Command) hasHelpCommand() bool {
+	if c.parent != nil || !c.helpEnabled || c.helpFlag == "" {
+		return false
+	}
+	if len(c.subcommands) == 0 && len(c.helpTopics) == 0 {
+		return false
+	}
+	for _, cmd := range c.subcommands {
+		if cmd.hasName(c.helpFlag) {
+			return false
+		}
+	}
+	return true
+}
+
+// runHelpCommand shows help for the command path or topic named by args
+func (c *Command) runHelpCommand(args []string) error {
+	target := c
+	for _, name := range args {
+		if strings.HasPrefix(name, "-") {
+			continue
+		}
+		if target == c {
+			if topic := c.helpTopic(name); topic != nil {
+				showHelpTopic(c.stdoutWriter(), topic)
+				return nil
+			}
+		}
+
+		cmd, err := target.findSubcommand(name)
+		if err != nil {
+			return err
+		}
+		if cmd == nil || !cmd.gateOpen(nil) {
+			notFound := target.commandNotFound(name).(*CommandNotFoundError)
+			if target == c {
+				notFound.Suggestions = mergeSuggestions(notFound.Suggestions, c.suggestHelpTopics(name))
+			}
+			return notFound
+		}
+		target = cmd
+	}
+
+	target.showHelp()
+	return nil
+}
+
+// helpTopic returns the topic with the given name, or nil
+func (c *Command) helpTopic(name string) *HelpTopic {
+	for i := range c.helpTopics {
+		if c.helpTopics[i].Name == name {
+			return &c.helpTopics[i]
+		}
+	}
+	return nil
+}
+
+// suggestHelpTopics returns the topics whose names are close to name
+func (c *Command) suggestHelpTopics(name string) []string {
+	if c.suggestionsDisabled {
+		return nil
+	}
+	best := make(map[string]int)
+	for _, topic := range c.helpTopics {
+		if d, ok := suggestionScore(name, topic.Name, c.maxSuggestionDistance()); ok {
+			best[topic.Name] = d
+		}
+	}
+	return rankSuggestions(best)
+}
+
+func mergeSuggestions(a, b []string) []string {
+	merged := append([]string{}, a...)
+	for _, suggestion := range b {
+		if !containsString(merged, suggestion) {
+			merged = append(merged, suggestion)
+		}
+	}
+	return merged
+}
+
+// showHelpTopic displays a help topic page
+func showHelpTopic(w io.Writer, topic *HelpTopic) {
+	fmt.Fprintf(w, "%s", color.Bold+topic.Name+color.Reset)
+	if topic.Description != "" {
+		fmt.Fprintf(w, " - %s", topic.Description)
+	}
+	fmt.Fprintln(w)
+	if topic.Body != "" {
+		fmt.Fprintf(w, "\n%s\n", strings.TrimRight(topic.Body, "\n"))
+	}
+}
+
+// showHelpTopics lists the help topics in the root help
+func (c *Command) showHelpTopics() {
+	w := c.stdoutWriter()
+	if !c.hasHelpCommand() || len(c.helpTopics) == 0 {
+		return
+	}
+
+	topics := append([]HelpTopic{}, c.helpTopics...)
+	if c.commandOrder == OrderAlphabetical {
+		sort.Slice(topics, func(i, j int) bool { return topics[i].Name < topics[j].Name })
+	}
+
+	fmt.Fprintf(w, "\n%s:\n", color.Bold+"Additional help topics"+color.Reset)
+	for _, topic := range topics {
+		fmt.Fprintf(w, "  %-15s %s\n", color.Cyan+topic.Name+color.Reset, topic.Description)
+	}
+	fmt.Fprintf(w, "\n%s \"%s %s <topic>\" %s\n",
+		color.Dim+"Use"+color.Reset,
+		c.name,
+		c.helpFlag,
+		color.Dim+"to read about a topic."+color.Reset)
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"strings"
 	"testing"
 )
 
@@ -206,3 +207,92 @@ func TestHelpForDeepNestedCommands(t *testing.T) {
 		})
 	}
 }
+
+// TestHelpSubcommand tests the built-in help command resolving command paths
+func TestHelpSubcommand(t *testing.T) {
+	root := Root("myapp")
+	deploy := Cmd("deploy").Description("Deploy the app")
+	deploy.AddCommand(Cmd("rollback").Description("Undo a deployment"))
+	root.AddCommand(deploy)
+
+	out := captureStdout(t, func() {
+		if err := root.ExecuteWithArgs([]string{"help", "deploy", "rollback"}); err != nil {
+			t.Errorf("help should not return error, got %v", err)
+		}
+	})
+	if !strings.Contains(out, "Undo a deployment") {
+		t.Errorf("expected rollback help, got:\n%s", out)
+	}
+
+	out = captureStdout(t, func() { root.ExecuteWithArgs([]string{"help"}) })
+	if !strings.Contains(out, "Help about any command or topic") {
+		t.Errorf("expected root help listing the help command, got:\n%s", out)
+	}
+
+	err := root.ExecuteWithArgs([]string{"help", "deplyo"})
+	notFound, ok := err.(*CommandNotFoundError)
+	if !ok || len(notFound.Suggestions) == 0 || notFound.Suggestions[0] != "deploy" {
+		t.Errorf("expected CommandNotFoundError suggesting deploy, got %v", err)
+	}
+}
+
+// TestHelpTopics tests help pages that are not commands
+func TestHelpTopics(t *testing.T) {
+	root := Root("myapp").
+		AddHelpTopic("environment", "Environment variables", "MYAPP_TOKEN  API token\n")
+	root.AddCommand(Cmd("deploy"))
+
+	out := captureStdout(t, func() {
+		if err := root.ExecuteWithArgs([]string{"help", "environment"}); err != nil {
+			t.Errorf("help topic should not return error, got %v", err)
+		}
+	})
+	if !strings.Contains(out, "MYAPP_TOKEN  API token") {
+		t.Errorf("expected topic body, got:\n%s", out)
+	}
+
+	out = captureStdout(t, root.ShowHelp)
+	if !strings.Contains(out, "Additional help topics") || !strings.Contains(out, "environment") {
+		t.Errorf("expected topics in root help, got:\n%s", out)
+	}
+
+	err := root.ExecuteWithArgs([]string{"help", "enviroment"})
+	if notFound, ok := err.(*CommandNotFoundError); !ok || !containsWord(notFound.Suggestions, "environment") {
+		t.Errorf("expected topic suggestion, got %v", err)
+	}
+	if got := root.GetHelpTopics(); len(got) != 1 || got[0].Name != "environment" {
+		t.Errorf("unexpected topics: %v", got)
+	}
+}
+
+// TestHelpSubcommandSwitches tests that the help command follows the help settings
+func TestHelpSubcommandSwitches(t *testing.T) {
+	ran := false
+	root := Root("myapp")
+	root.AddCommand(Cmd("deploy"))
+
+	// Named after the long help flag
+	root.SetHelpFlag("ayuda", "a")
+	if err := root.ExecuteWithArgs([]string{"ayuda", "deploy"}); err != nil {
+		t.Errorf("renamed help command failed: %v", err)
+	}
+	if !containsWord(getCompletionWords(root, nil), "ayuda") {
+		t.Error("help command should be completed")
+	}
+
+	// Disabled with the help flag
+	root.SetHelpFlag("help", "h").DisableHelp()
+	if _, ok := root.ExecuteWithArgs([]string{"help"}).(*CommandNotFoundError); !ok {
+		t.Error("help command should not exist when help is disabled")
+	}
+
+	// A real subcommand takes precedence
+	root.EnableHelp()
+	root.AddCommand(Cmd("help").Action(func(ctx context.Context, c *Command) error {
+		ran = true
+		return nil
+	}))
+	if err := root.ExecuteWithArgs([]string{"help"}); err != nil || !ran {
+		t.Errorf("user-defined help command should run, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import "fmt"
+
+// AddLazyCommand registers a subcommand that is built by build only when
+// needed: when execution dispatches into it, when help is shown for it, or
+// when the tree is walked or validated. Listings use the name and description
+// given here, so building the command can be deferred for large trees.
+// build must return a command with the same name.
+func (c *Command) AddLazyCommand(name, description string, build func() *Command) *Command {
+	return c.AddCommand(Lazy(name, description, build))
+}
+
+// Lazy returns a placeholder for a subcommand built by build only when
+// needed, to add with AddCommand (see AddLazyCommand). Listing and dispatch
+// settings must be made on the placeholder to apply before the command is
+// built: aliases, group, hidden, deprecation, experimental status and gates.
+// They are copied onto the built command.
+//
+//	root.AddCommand(cli.Lazy("remove", "Remove items", newRemoveCommand).Aliases("rm"))
+func Lazy(name, description string, build func() *Command) *Command {
+	placeholder := Cmd(name).Description(description)
+	placeholder.lazy = build
+	return placeholder
+}
+
+// IsLazy reports whether the command is a placeholder that has not been built yet
+func (c *Command) IsLazy() bool {
+	return c.lazy != nil
+}
+
+// materialize builds a lazy placeholder and swaps it for the real command in
+// its parent, returning the real command. Other commands are returned as is.
+func (c *Command) materialize() *Command {
+	if c.lazy == nil {
+		return c
+	}
+
+	build := c.lazy
+	c.lazy = nil
+	cmd := build()
+	if cmd == nil || cmd.name != c.name {
+		panic(fmt.Sprintf("lazy command '%s' must build a command with the same name", c.getCommandPath()))
+	}
+	if cmd.description == "" {
+		cmd.description = c.description
+	}
+	c.copyListing(cmd)
+
+	if c.parent != nil {
+		c.parent.ReplaceCommand(cmd)
+	}
+	return cmd
+}
+
+// copyListing copies the settings made on a lazy placeholder onto the built
+// command cmd
+func (c *Command) copyListing(cmd *Command) {
+	if len(c.aliases) > 0 {
+		cmd.aliases = c.aliases
+	}
+	if c.group != "" {
+		cmd.group = c.group
+	}
+	if c.hidden {
+		cmd.hidden = true
+	}
+	if c.deprecated != "" {
+		cmd.deprecated = c.deprecated
+	}
+	if c.experimental {
+		cmd.experimental = true
+	}
+	if c.gateEnv != "" {
+		cmd.gateEnv = c.gateEnv
+	}
+	if c.gateFlag != "" {
+		cmd.gateFlag = c.gateFlag
+	}
+}
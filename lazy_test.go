@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// lazyTree returns a root with a lazy "db" command and a counter of builds
+func lazyTree(ran *string) (*Command, *int) {
+	builds := 0
+	root := Root("app")
+	root.AddCommand(Cmd("status"))
+	root.AddLazyCommand("db", "Database tools", func() *Command {
+		builds++
+		var force bool
+		db := Cmd("db").Flag(&force, "force", "f", false, "Force")
+		db.AddCommand(Cmd("migrate").Action(func(ctx context.Context, c *Command) error {
+			*ran = c.GetName()
+			return nil
+		}))
+		return db
+	})
+	return root, &builds
+}
+
+// TestLazyCommandDeferred tests that lazy commands are not built for listings
+func TestLazyCommandDeferred(t *testing.T) {
+	var ran string
+	root, builds := lazyTree(&ran)
+
+	if err := root.ExecuteWithArgs([]string{"status"}); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	out := captureStdout(t, root.ShowHelp)
+	if !strings.Contains(out, "Database tools") {
+		t.Errorf("root help should list the lazy command:\n%s", out)
+	}
+	if !containsWord(getCompletionWords(root, nil), "db") {
+		t.Error("completion should offer the lazy command")
+	}
+	AddCompletion(root)
+
+	if *builds != 0 {
+		t.Errorf("lazy command should not be built yet, built %d times", *builds)
+	}
+	if db := root.GetCommands()["db"]; !db.IsLazy() {
+		t.Error("placeholder should report IsLazy")
+	}
+}
+
+// TestLazyCommandMaterialized tests building lazy commands on dispatch
+func TestLazyCommandMaterialized(t *testing.T) {
+	var ran string
+	root, builds := lazyTree(&ran)
+	AddCompletion(root)
+
+	if err := root.ExecuteWithArgs([]string{"db", "--force", "migrate"}); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if ran != "migrate" || *builds != 1 {
+		t.Errorf("expected migrate to run after one build, got %q after %d", ran, *builds)
+	}
+
+	db := root.GetCommands()["db"]
+	if db.IsLazy() || db.GetParent() != root || db.GetDescription() != "Database tools" {
+		t.Error("built command should replace the placeholder")
+	}
+
+	if err := root.ExecuteWithArgs([]string{"db", "migrate"}); err != nil || *builds != 1 {
+		t.Errorf("command should only be built once, built %d times (%v)", *builds, err)
+	}
+}
+
+// TestLazyCommandTraversal tests that Find, Walk and Validate build lazy commands
+func TestLazyCommandTraversal(t *testing.T) {
+	var ran string
+	root, builds := lazyTree(&ran)
+
+	if cmd, err := root.Find("db migrate"); err != nil || cmd.GetName() != "migrate" {
+		t.Fatalf("Find should build the lazy command, got %v", err)
+	}
+
+	root2, builds2 := lazyTree(&ran)
+	var names []string
+	root2.Walk(func(cmd *Command) error {
+		names = append(names, cmd.GetName())
+		return nil
+	})
+	if strings.Join(names, ",") != "app,db,migrate,status" || *builds2 != 1 {
+		t.Errorf("unexpected walk %v after %d builds", names, *builds2)
+	}
+
+	if err := root.Validate(); err != nil || *builds != 1 {
+		t.Errorf("Validate failed or rebuilt: %v (%d builds)", err, *builds)
+	}
+}
+
+// TestLazyCommandNameMismatch tests that builders must keep the registered name
+func TestLazyCommandNameMismatch(t *testing.T) {
+	root := Root("app")
+	root.AddLazyCommand("db", "", func() *Command { return Cmd("database") })
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for a mismatched name")
+		}
+	}()
+	root.ExecuteWithArgs([]string{"db"})
+}
+
+// TestLazyCommandListing tests settings made on a lazy placeholder
+func TestLazyCommandListing(t *testing.T) {
+	var removed []string
+	builds := 0
+	root := Root("app").AddGroup("items", "Item Commands")
+	root.AddCommand(Lazy("remove", "Remove items", func() *Command {
+		builds++
+		return Cmd("remove").Action(func(ctx context.Context, c *Command, names ...string) error {
+			removed = names
+			return nil
+		})
+	}).Aliases("rm").Group("items"))
+	root.AddCommand(Lazy("debug", "Debug tools", func() *Command { return Cmd("debug") }).Hidden())
+	root.AddCommand(Lazy("beta", "Beta tools", func() *Command { return Cmd("beta") }).GateEnv("APP_TEST_LAZY_BETA"))
+
+	out := captureStdout(t, root.ShowHelp)
+	if !strings.Contains(out, "Item Commands") || !strings.Contains(out, "aliases: rm") {
+		t.Errorf("lazy command should be listed in its group with aliases:\n%s", out)
+	}
+	if strings.Contains(out, "Debug tools") || strings.Contains(out, "Beta tools") {
+		t.Errorf("hidden and gated lazy commands should not be listed:\n%s", out)
+	}
+	if !containsWord(getCompletionWords(root, nil), "rm") {
+		t.Error("completion should offer the lazy command's alias")
+	}
+	if builds != 0 {
+		t.Fatalf("listing should not build the command, built %d times", builds)
+	}
+
+	if err := root.ExecuteWithArgs([]string{"rm", "a", "b"}); err != nil {
+		t.Fatalf("dispatch by alias failed: %v", err)
+	}
+	if builds != 1 || strings.Join(removed, ",") != "a,b" {
+		t.Errorf("expected remove to run with [a b], builds=%d removed=%v", builds, removed)
+	}
+
+	remove, err := root.Find("rm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remove.IsLazy() || strings.Join(remove.GetAliases(), ",") != "rm" || remove.GetGroup() != "items" {
+		t.Errorf("settings should be copied to the built command: aliases=%v group=%q", remove.GetAliases(), remove.GetGroup())
+	}
+
+	if _, ok := root.ExecuteWithArgs([]string{"beta"}).(*CommandNotFoundError); !ok {
+		t.Error("gated lazy command should not be found while the gate is closed")
+	}
+}
@@ -245,3 +245,79 @@ func TestLifecycleMultiplePersistentLevels(t *testing.T) {
 		}
 	}
 }
+
+// TestLifecycleArgumentErrorsSkipHooks tests that argument errors are reported before any hook runs
+func TestLifecycleArgumentErrorsSkipHooks(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"missing required argument", []string{"deploy"}},
+		{"invalid argument type", []string{"deploy", "prod", "many"}},
+		{"invalid variadic element", []string{"deploy", "prod", "3", "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hooks := []string{}
+
+			root := Root("app").
+				PersistentPreRun(func(ctx context.Context, c *Command) error {
+					hooks = append(hooks, "persistent-pre")
+					return nil
+				}).
+				PersistentPostRun(func(ctx context.Context, c *Command) error {
+					hooks = append(hooks, "persistent-post")
+					return nil
+				})
+
+			deploy := Cmd("deploy").
+				Arg("env", "Environment", true).
+				Arg("replicas", "Replica count", false).
+				PreRun(func(ctx context.Context, c *Command) error {
+					hooks = append(hooks, "pre")
+					return nil
+				}).
+				Action(func(ctx context.Context, c *Command, env string, replicas int, ports ...int) error {
+					hooks = append(hooks, "action")
+					return nil
+				})
+			root.AddCommand(deploy)
+
+			err := root.ExecuteWithArgs(tt.args)
+			if _, ok := err.(*ArgumentError); !ok {
+				t.Fatalf("expected ArgumentError, got %T (%v)", err, err)
+			}
+			if len(hooks) != 0 {
+				t.Errorf("expected no hooks to run, got %v", hooks)
+			}
+		})
+	}
+}
+
+// TestLifecycleRequiredArgWithoutParam tests that declared required args are checked even if the action ignores them
+func TestLifecycleRequiredArgWithoutParam(t *testing.T) {
+	preRan := false
+
+	cmd := Root("test").
+		Arg("name", "Name", true).
+		PreRun(func(ctx context.Context, c *Command) error {
+			preRan = true
+			return nil
+		}).
+		Action(func(ctx context.Context, c *Command) error {
+			return nil
+		})
+
+	err := cmd.ExecuteWithArgs([]string{})
+	argErr, ok := err.(*ArgumentError)
+	if !ok {
+		t.Fatalf("expected ArgumentError, got %T", err)
+	}
+	if argErr.Arg != "name" {
+		t.Errorf("expected error for 'name', got %q", argErr.Arg)
+	}
+	if preRan {
+		t.Error("PreRun should not run when a required argument is missing")
+	}
+}
@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// ExecuteLine splits line into arguments with SplitLine and executes them,
+// after resetting the flags of the whole tree to their initial values, so
+// the same tree can run many lines (REPLs, batch files, bots, tests).
+// Environment variables are expanded when enabled on the root.
+func (c *Command) ExecuteLine(ctx context.Context, line string) error {
+	args, err := c.root().splitLine(line)
+	if err != nil {
+		return err
+	}
+	c.root().resetFlags()
+	return c.execute(ctx, args)
+}
+
+// EnableEnvExpansion expands $VAR and ${VAR} in lines run by ExecuteLine and
+// the shell (set on the root)
+func (c *Command) EnableEnvExpansion() *Command {
+	c.envExpansion = true
+	return c
+}
+
+// DisableEnvExpansion keeps "$" literal in lines run by ExecuteLine and the
+// shell (default)
+func (c *Command) DisableEnvExpansion() *Command {
+	c.envExpansion = false
+	return c
+}
+
+// splitLine splits a line with the root's expansion setting
+func (c *Command) splitLine(line string) ([]string, error) {
+	var lookup func(string) (string, bool)
+	if c.envExpansion {
+		lookup = os.LookupEnv
+	}
+	return SplitLine(line, lookup)
+}
+
+// SplitLine splits a command line into words following POSIX shell rules:
+//   - blanks separate words, and "#" at the start of a word begins a comment
+//   - a backslash escapes the next character; backslash-newline is removed
+//   - single quotes keep everything literal up to the closing quote
+//   - double quotes keep blanks; a backslash only escapes $ ` " \ and newline
+//
+// With a non-nil lookup, $NAME and ${NAME} are expanded (unset variables
+// expand to ""). Unquoted expansions are split into words on blanks; quoted
+// ones are not. Unterminated quotes, escapes and ${ are returned as a
+// *LineError with the column where they start.
+func SplitLine(line string, lookup func(name string) (string, bool)) ([]string, error) {
+	s := &lineSplitter{line: line, runes: []rune(line), lookup: lookup}
+	return s.split()
+}
+
+// lineSplitter holds the state of SplitLine
+type lineSplitter struct {
+	line   string
+	runes  []rune
+	lookup func(string) (string, bool)
+	i      int // Position in runes
+
+	words  []string
+	word   strings.Builder
+	inWord bool // A word has started, even if still empty ("" or '')
+}
+
+func (s *lineSplitter) split() ([]string, error) {
+	for s.i < len(s.runes) {
+		r := s.runes[s.i]
+		switch {
+		case isBlank(r):
+			s.endWord()
+			s.i++
+		case r == '#' && !s.inWord:
+			s.i = len(s.runes)
+		case r == '\\':
+			if s.i+1 == len(s.runes) {
+				return nil, s.errorAt(s.i, "unterminated escape")
+			}
+			if next := s.runes[s.i+1]; next != '\n' {
+				s.add(next)
+			}
+			s.i += 2
+		case r == '\'':
+			if err := s.singleQuoted(); err != nil {
+				return nil, err
+			}
+		case r == '"':
+			if err := s.doubleQuoted(); err != nil {
+				return nil, err
+			}
+		case r == '$':
+			value, expanded, err := s.expand()
+			if err != nil {
+				return nil, err
+			}
+			for _, v := range value {
+				if expanded && isBlank(v) {
+					s.endWord()
+				} else {
+					s.add(v)
+				}
+			}
+		default:
+			s.add(r)
+			s.i++
+		}
+	}
+	s.endWord()
+	return s.words, nil
+}
+
+// singleQuoted reads a single-quoted string starting at s.i
+func (s *lineSplitter) singleQuoted() error {
+	start := s.i
+	for end := start + 1; end < len(s.runes); end++ {
+		if s.runes[end] == '\'' {
+			s.inWord = true
+			s.word.WriteString(string(s.runes[start+1 : end]))
+			s.i = end + 1
+			return nil
+		}
+	}
+	return s.errorAt(start, "unterminated single quote")
+}
+
+// doubleQuoted reads a double-quoted string starting at s.i
+func (s *lineSplitter) doubleQuoted() error {
+	start := s.i
+	s.inWord = true
+	s.i++
+	for s.i < len(s.runes) {
+		r := s.runes[s.i]
+		switch {
+		case r == '"':
+			s.i++
+			return nil
+		case r == '\\' && s.i+1 < len(s.runes) && strings.ContainsRune("$`\"\\\n", s.runes[s.i+1]):
+			if next := s.runes[s.i+1]; next != '\n' {
+				s.word.WriteRune(next)
+			}
+			s.i += 2
+		case r == '$':
+			value, _, err := s.expand()
+			if err != nil {
+				return err
+			}
+			s.word.WriteString(value)
+		default:
+			s.word.WriteRune(r)
+			s.i++
+		}
+	}
+	return s.errorAt(start, "unterminated double quote")
+}
+
+// expand reads a "$" at s.i. It returns the variable's value and true, or
+// "$" and false when expansion is disabled or no variable name follows.
+func (s *lineSplitter) expand() (string, bool, error) {
+	start := s.i
+	s.i++
+	if s.lookup == nil {
+		return "$", false, nil
+	}
+
+	if s.i < len(s.runes) && s.runes[s.i] == '{' {
+		for end := s.i + 1; end < len(s.runes); end++ {
+			if s.runes[end] != '}' {
+				continue
+			}
+			name := string(s.runes[s.i+1 : end])
+			if !isVarName(name) {
+				return "", false, s.errorAt(start, fmt.Sprintf("bad substitution '${%s}'", name))
+			}
+			s.i = end + 1
+			value, _ := s.lookup(name)
+			return value, true, nil
+		}
+		return "", false, s.errorAt(start, "unterminated '${'")
+	}
+
+	end := s.i
+	for end < len(s.runes) && isVarName(string(s.runes[s.i:end+1])) {
+		end++
+	}
+	if end == s.i {
+		return "$", false, nil
+	}
+	name := string(s.runes[s.i:end])
+	s.i = end
+	value, _ := s.lookup(name)
+	return value, true, nil
+}
+
+// add appends a rune to the current word
+func (s *lineSplitter) add(r rune) {
+	s.inWord = true
+	s.word.WriteRune(r)
+}
+
+// endWord finishes the current word, if one was started
+func (s *lineSplitter) endWord() {
+	if s.inWord {
+		s.words = append(s.words, s.word.String())
+		s.word.Reset()
+		s.inWord = false
+	}
+}
+
+// errorAt returns a LineError for the rune at position i
+func (s *lineSplitter) errorAt(i int, msg string) error {
+	return &LineError{
+		Line:   s.line,
+		Column: i + 1,
+		Msg:    msg,
+	}
+}
+
+// isBlank reports whether r separates words
+func isBlank(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n'
+}
+
+// isVarName reports whether name is a valid environment variable name
+func isVarName(name string) bool {
+	for i, r := range name {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return name != ""
+}
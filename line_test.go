@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestSplitLine tests POSIX word splitting
+func TestSplitLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"", nil},
+		{"  deploy   --env=prod\tapi  ", []string{"deploy", "--env=prod", "api"}},
+		{`deploy 'my app' "your app"`, []string{"deploy", "my app", "your app"}},
+		{`a'b'"c"d`, []string{"abcd"}},
+		{`'' ""`, []string{"", ""}},
+		{`it\'s a\ b \\`, []string{"it's", "a b", `\`}},
+		{`'no \escapes $HOME'`, []string{`no \escapes $HOME`}},
+		{`"a \"b\" \$c \d \\"`, []string{`a "b" $c \d \`}},
+		{"one \\\ntwo", []string{"one", "two"}},
+		{"say hi # comment", []string{"say", "hi"}},
+		{"a#b", []string{"a#b"}},
+		{"$HOME ${USER}", []string{"$HOME", "${USER}"}},
+		{"héllo 'wörld'", []string{"héllo", "wörld"}},
+	}
+	for _, tt := range tests {
+		got, err := SplitLine(tt.line, nil)
+		if err != nil {
+			t.Errorf("SplitLine(%q) failed: %v", tt.line, err)
+			continue
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+			t.Errorf("SplitLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+// TestSplitLineEnv tests environment variable expansion
+func TestSplitLineEnv(t *testing.T) {
+	env := map[string]string{
+		"NAME":  "world",
+		"ARGS":  " a  b ",
+		"EMPTY": "",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"hello $NAME", []string{"hello", "world"}},
+		{"${NAME}s x$NAME.y", []string{"worlds", "xworld.y"}},
+		{"run $ARGS end", []string{"run", "a", "b", "end"}},
+		{`run "$ARGS"`, []string{"run", " a  b "}},
+		{"x${ARGS}y", []string{"x", "a", "b", "y"}},
+		{`$EMPTY $UNSET "$EMPTY"`, []string{""}},
+		{`'$NAME' \$NAME`, []string{"$NAME", "$NAME"}},
+		{"cost $5 $", []string{"cost", "$5", "$"}},
+	}
+	for _, tt := range tests {
+		got, err := SplitLine(tt.line, lookup)
+		if err != nil {
+			t.Errorf("SplitLine(%q) failed: %v", tt.line, err)
+			continue
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+			t.Errorf("SplitLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+// TestSplitLineErrors tests column positions of splitting errors
+func TestSplitLineErrors(t *testing.T) {
+	lookup := func(name string) (string, bool) { return "", false }
+	tests := []struct {
+		line   string
+		column int
+		msg    string
+	}{
+		{`deploy 'my app`, 8, "unterminated single quote"},
+		{`deploy --name="my app`, 15, "unterminated double quote"},
+		{`é "x\"`, 3, "unterminated double quote"},
+		{`deploy \`, 8, "unterminated escape"},
+		{`echo ${HOME`, 6, "unterminated '${'"},
+		{`echo "${1x}"`, 7, "bad substitution '${1x}'"},
+	}
+	for _, tt := range tests {
+		_, err := SplitLine(tt.line, lookup)
+		var lineErr *LineError
+		if !errors.As(err, &lineErr) {
+			t.Errorf("SplitLine(%q): expected LineError, got %v", tt.line, err)
+			continue
+		}
+		if lineErr.Column != tt.column || lineErr.Msg != tt.msg || lineErr.Line != tt.line {
+			t.Errorf("SplitLine(%q) = %+v, want column %d %q", tt.line, lineErr, tt.column, tt.msg)
+		}
+	}
+}
+
+// TestExecuteLine tests executing lines with fresh flag state
+func TestExecuteLine(t *testing.T) {
+	var env string
+	var got []string
+	root := Root("app")
+	root.AddCommand(Cmd("deploy").
+		Flag(&env, "env", "", "dev", "Environment").
+		Action(func(ctx context.Context, cmd *Command, services ...string) error {
+			got = append(got, env+":"+strings.Join(services, ","))
+			return nil
+		}))
+
+	ctx := context.Background()
+	if err := root.ExecuteLine(ctx, `deploy --env=prod 'my app' api`); err != nil {
+		t.Fatalf("ExecuteLine failed: %v", err)
+	}
+	if err := root.ExecuteLine(ctx, `deploy "$APP_SERVICE"`); err != nil {
+		t.Fatalf("ExecuteLine failed: %v", err)
+	}
+
+	t.Setenv("APP_SERVICE", "web")
+	root.EnableEnvExpansion()
+	if err := root.ExecuteLine(ctx, `deploy $APP_SERVICE`); err != nil {
+		t.Fatalf("ExecuteLine failed: %v", err)
+	}
+
+	want := "prod:my app,api|dev:$APP_SERVICE|dev:web"
+	if strings.Join(got, "|") != want {
+		t.Errorf("unexpected runs: %q", got)
+	}
+
+	var lineErr *LineError
+	if err := root.ExecuteLine(ctx, `deploy "oops`); !errors.As(err, &lineErr) || lineErr.Column != 8 {
+		t.Errorf("expected LineError at column 8, got %v", err)
+	}
+	if len(got) != 3 {
+		t.Error("a line that fails to split should not run")
+	}
+}
@@ -0,0 +1,326 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// errInterrupted is returned by readLine when the line is abandoned with Ctrl-C
+var errInterrupted = errors.New("interrupted")
+
+// lineEditor reads lines with emacs-style editing keys, history and tab
+// completion. On a terminal it switches to raw mode while reading and redraws
+// the line; on other inputs (pipes, files) the same keys are interpreted but
+// nothing is echoed.
+type lineEditor struct {
+	in       inputReader
+	file     *os.File // Input file, for raw mode (nil if not a file)
+	out      io.Writer
+	complete func(line string) (word string, candidates []string)
+	history  []string
+
+	raw    bool   // Terminal is in raw mode: echo and redraw
+	prompt string // Prompt of the current line
+	buf    []rune // Current line
+	pos    int    // Cursor position in buf
+}
+
+// newLineEditor creates an editor reading from in and writing to out.
+// complete returns the word being completed at the end of line and its
+// candidates.
+func newLineEditor(in io.Reader, out io.Writer, complete func(line string) (string, []string)) *lineEditor {
+	file, _ := in.(*os.File)
+	return &lineEditor{
+		in:       inputReader{in},
+		file:     file,
+		out:      out,
+		complete: complete,
+	}
+}
+
+// inputReader reads runes one byte at a time, without reading ahead, so
+// that commands run between lines can read the rest of the input
+type inputReader struct {
+	r io.Reader
+}
+
+// ReadByte reads a single byte
+func (in inputReader) ReadByte() (byte, error) {
+	var b [1]byte
+	for {
+		n, err := in.r.Read(b[:])
+		if n == 1 {
+			return b[0], nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// ReadRune reads a UTF-8 encoded rune
+func (in inputReader) ReadRune() (rune, int, error) {
+	b, err := in.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	buf := []byte{b}
+	for !utf8.FullRune(buf) {
+		if b, err = in.ReadByte(); err != nil {
+			break
+		}
+		buf = append(buf, b)
+	}
+	r, size := utf8.DecodeRune(buf)
+	return r, size, nil
+}
+
+// addHistory appends a line to the history, skipping repeats
+func (e *lineEditor) addHistory(line string) {
+	if n := len(e.history); n > 0 && e.history[n-1] == line {
+		return
+	}
+	e.history = append(e.history, line)
+}
+
+// readLine reads one line after printing prompt. It returns io.EOF on Ctrl-D
+// or end of input with an empty line, and errInterrupted on Ctrl-C.
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	if restore, err := makeRaw(e.file); err == nil {
+		e.raw = true
+		defer func() {
+			restore()
+			e.raw = false
+		}()
+	}
+
+	e.prompt, e.buf, e.pos = prompt, nil, 0
+	histIndex, saved := len(e.history), ""
+	fmt.Fprint(e.out, prompt)
+
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			if err == io.EOF && len(e.buf) > 0 {
+				e.newline()
+				return string(e.buf), nil
+			}
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			// "\r" is Enter on a raw terminal; elsewhere it is part of "\r\n"
+			if r == '\r' && !e.raw {
+				continue
+			}
+			e.newline()
+			return string(e.buf), nil
+		case 0x03: // Ctrl-C
+			if e.raw {
+				fmt.Fprint(e.out, "^C")
+			}
+			e.newline()
+			return "", errInterrupted
+		case 0x04: // Ctrl-D
+			if len(e.buf) == 0 {
+				e.newline()
+				return "", io.EOF
+			}
+			e.deleteAt(e.pos)
+		case 0x7f, 0x08: // Backspace
+			if e.pos > 0 {
+				e.pos--
+				e.deleteAt(e.pos)
+			}
+		case 0x01: // Ctrl-A
+			e.moveTo(0)
+		case 0x05: // Ctrl-E
+			e.moveTo(len(e.buf))
+		case 0x02: // Ctrl-B
+			e.moveTo(e.pos - 1)
+		case 0x06: // Ctrl-F
+			e.moveTo(e.pos + 1)
+		case 0x0b: // Ctrl-K
+			e.buf = e.buf[:e.pos]
+			e.refresh()
+		case 0x15: // Ctrl-U
+			e.buf = append([]rune(nil), e.buf[e.pos:]...)
+			e.pos = 0
+			e.refresh()
+		case 0x17: // Ctrl-W
+			start := e.pos
+			for start > 0 && e.buf[start-1] == ' ' {
+				start--
+			}
+			for start > 0 && e.buf[start-1] != ' ' {
+				start--
+			}
+			e.buf = append(e.buf[:start], e.buf[e.pos:]...)
+			e.pos = start
+			e.refresh()
+		case 0x10, 0x0e: // Ctrl-P, Ctrl-N
+			histIndex, saved = e.browseHistory(r == 0x10, histIndex, saved)
+		case '\t':
+			e.completeWord()
+		case 0x1b: // Escape sequence
+			switch e.readEscape() {
+			case 'A':
+				histIndex, saved = e.browseHistory(true, histIndex, saved)
+			case 'B':
+				histIndex, saved = e.browseHistory(false, histIndex, saved)
+			case 'C':
+				e.moveTo(e.pos + 1)
+			case 'D':
+				e.moveTo(e.pos - 1)
+			case 'H':
+				e.moveTo(0)
+			case 'F':
+				e.moveTo(len(e.buf))
+			case '~':
+				e.deleteAt(e.pos)
+			}
+		default:
+			if r >= ' ' {
+				e.insert([]rune{r})
+			}
+		}
+	}
+}
+
+// readEscape reads the rest of an escape sequence and returns its final
+// key: 'A'-'D' for arrows, 'H'/'F' for home/end and '~' for delete
+func (e *lineEditor) readEscape() rune {
+	r, _, err := e.in.ReadRune()
+	if err != nil || (r != '[' && r != 'O') {
+		return 0
+	}
+
+	var params []rune
+	for {
+		r, _, err = e.in.ReadRune()
+		if err != nil {
+			return 0
+		}
+		if r < '0' || r > '9' {
+			break
+		}
+		params = append(params, r)
+	}
+
+	if r == '~' {
+		switch string(params) {
+		case "1", "7":
+			return 'H'
+		case "4", "8":
+			return 'F'
+		case "3":
+			return '~'
+		}
+		return 0
+	}
+	return r
+}
+
+// browseHistory replaces the line with the previous or next history entry,
+// keeping the line being edited as the entry after the last one
+func (e *lineEditor) browseHistory(back bool, index int, saved string) (int, string) {
+	switch {
+	case back && index > 0:
+		if index == len(e.history) {
+			saved = string(e.buf)
+		}
+		index--
+		e.buf = []rune(e.history[index])
+	case !back && index < len(e.history):
+		index++
+		if index == len(e.history) {
+			e.buf = []rune(saved)
+		} else {
+			e.buf = []rune(e.history[index])
+		}
+	default:
+		return index, saved
+	}
+	e.pos = len(e.buf)
+	e.refresh()
+	return index, saved
+}
+
+// completeWord completes the word before the cursor: a single candidate is
+// inserted with a trailing space, several are completed to their common
+// prefix and listed if that adds nothing
+func (e *lineEditor) completeWord() {
+	if e.complete == nil {
+		return
+	}
+	word, candidates := e.complete(string(e.buf[:e.pos]))
+	if len(candidates) == 0 {
+		return
+	}
+
+	common := candidates[0]
+	for _, candidate := range candidates[1:] {
+		for !strings.HasPrefix(candidate, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	if len(candidates) == 1 {
+		common += " "
+	}
+
+	if len(common) > len(word) && strings.HasPrefix(common, word) {
+		e.insert([]rune(common[len(word):]))
+		return
+	}
+	if len(candidates) > 1 {
+		fmt.Fprintf(e.out, "\n%s\n%s%s", strings.Join(candidates, "  "), e.prompt, string(e.buf))
+		e.pos = len(e.buf)
+	}
+}
+
+// insert inserts runes at the cursor
+func (e *lineEditor) insert(runes []rune) {
+	tail := append(runes, e.buf[e.pos:]...)
+	e.buf = append(e.buf[:e.pos], tail...)
+	e.pos += len(runes)
+	e.refresh()
+}
+
+// deleteAt removes the rune at position i, if any
+func (e *lineEditor) deleteAt(i int) {
+	if i < 0 || i >= len(e.buf) {
+		return
+	}
+	e.buf = append(e.buf[:i], e.buf[i+1:]...)
+	e.refresh()
+}
+
+// moveTo moves the cursor to position i, within the line
+func (e *lineEditor) moveTo(i int) {
+	e.pos = max(0, min(i, len(e.buf)))
+	e.refresh()
+}
+
+// refresh redraws the line and places the cursor (raw mode only)
+func (e *lineEditor) refresh() {
+	if !e.raw {
+		return
+	}
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", e.prompt, string(e.buf))
+	if back := len(e.buf) - e.pos; back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+// newline ends the line on the terminal (raw mode only; otherwise the
+// terminal or the input already did)
+func (e *lineEditor) newline() {
+	if e.raw {
+		fmt.Fprint(e.out, "\n")
+	}
+}
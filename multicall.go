@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// EnableMultiCall makes Execute dispatch on the program name (busybox-style):
+// when the binary is invoked through a link named after a subcommand or one
+// of its aliases, that subcommand runs directly. Set it on the root.
+func (c *Command) EnableMultiCall() *Command {
+	c.multiCall = true
+	return c
+}
+
+// DisableMultiCall ignores the program name (default)
+func (c *Command) DisableMultiCall() *Command {
+	c.multiCall = false
+	return c
+}
+
+// multiCallArgs returns args prefixed with the subcommand named by argv0,
+// if multi-call dispatch is enabled and argv0 names one
+func (c *Command) multiCallArgs(argv0 string, args []string) []string {
+	if !c.multiCall {
+		return args
+	}
+
+	name := programName(argv0)
+	if name == c.name {
+		return args
+	}
+	for _, cmd := range c.sortedSubcommands() {
+		if cmd.hasName(name) {
+			return append([]string{cmd.name}, args...)
+		}
+	}
+	return args
+}
+
+// programName returns the base name of argv0 without a Windows executable extension
+func programName(argv0 string) string {
+	name := filepath.Base(argv0)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
+}
+
+// CreateLinks creates a symlink in dir for each visible subcommand, pointing
+// to target (default: the running executable), for installing a multi-call
+// binary. Existing links are replaced; other files are left alone and
+// reported. It returns the paths of the links created.
+func (c *Command) CreateLinks(dir, target string) ([]string, error) {
+	if target == "" {
+		executable, err := os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("cannot determine executable: %w", err)
+		}
+		target = executable
+	}
+
+	var created []string
+	for _, cmd := range c.sortedSubcommands() {
+		if !cmd.isListed() {
+			continue
+		}
+
+		link := filepath.Join(dir, cmd.name)
+		if info, err := os.Lstat(link); err == nil {
+			if info.Mode()&os.ModeSymlink == 0 {
+				return created, fmt.Errorf("cannot create link %s: file exists", link)
+			}
+			if err := os.Remove(link); err != nil {
+				return created, err
+			}
+		}
+		if err := os.Symlink(target, link); err != nil {
+			return created, err
+		}
+		created = append(created, link)
+	}
+	return created, nil
+}
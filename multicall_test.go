@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// TestMultiCallDispatch tests dispatching on the program name
+func TestMultiCallDispatch(t *testing.T) {
+	var ran string
+	var gotArgs []string
+	action := func(ctx context.Context, c *Command, args ...string) error {
+		ran = c.GetName()
+		gotArgs = args
+		return nil
+	}
+	root := Root("toolbox").EnableMultiCall()
+	root.AddCommand(Cmd("ls").Aliases("dir").Action(action))
+	root.AddCommand(Cmd("cat").Action(action))
+
+	originalArgs := os.Args
+	t.Cleanup(func() { os.Args = originalArgs })
+
+	tests := []struct {
+		argv    []string
+		wantCmd string
+	}{
+		{[]string{"/usr/bin/ls", "-"}, "ls"},
+		{[]string{"dir", "-"}, "ls"},
+		{[]string{"./toolbox", "cat", "-"}, "cat"},
+	}
+	for _, tt := range tests {
+		ran, gotArgs = "", nil
+		os.Args = tt.argv
+		if err := root.Execute(); err != nil {
+			t.Fatalf("%v: execution failed: %v", tt.argv, err)
+		}
+		if ran != tt.wantCmd || len(gotArgs) != 1 || gotArgs[0] != "-" {
+			t.Errorf("%v: expected %s with [-], got %s with %v", tt.argv, tt.wantCmd, ran, gotArgs)
+		}
+	}
+
+	// Unknown program names fall back to normal dispatch
+	if got := root.multiCallArgs("/bin/other", []string{"cat"}); len(got) != 1 || got[0] != "cat" {
+		t.Errorf("unexpected args for unknown program name: %v", got)
+	}
+
+	root.DisableMultiCall()
+	if got := root.multiCallArgs("/usr/bin/ls", []string{"x"}); len(got) != 1 || got[0] != "x" {
+		t.Errorf("program name should be ignored when disabled, got %v", got)
+	}
+}
+
+// TestMultiCallRegistered tests dispatching to a registered command on the first run
+func TestMultiCallRegistered(t *testing.T) {
+	useEmptyRegistry(t)
+
+	ran := false
+	Register("tool", Cmd("tool").Action(func(ctx context.Context, c *Command) error {
+		ran = true
+		return nil
+	}))
+	root := Root("toolbox").UseRegistry().EnableMultiCall()
+
+	originalArgs := os.Args
+	t.Cleanup(func() { os.Args = originalArgs })
+	os.Args = []string{"/usr/bin/tool"}
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if !ran {
+		t.Error("registered command should run on the first Execute")
+	}
+}
+
+// TestCreateLinks tests creating symlinks for a multi-call binary
+func TestCreateLinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require privileges on windows")
+	}
+	dir := t.TempDir()
+	target := filepath.Join(dir, "toolbox")
+
+	root := Root("toolbox")
+	root.AddCommand(Cmd("ls"))
+	root.AddCommand(Cmd("cat"))
+	root.AddCommand(Cmd("secret").Hidden())
+
+	// A stale link is replaced
+	if err := os.Symlink("/nonexistent", filepath.Join(dir, "ls")); err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := root.CreateLinks(dir, target)
+	if err != nil {
+		t.Fatalf("CreateLinks failed: %v", err)
+	}
+	if len(created) != 2 {
+		t.Fatalf("expected 2 links, got %v", created)
+	}
+	for _, name := range []string{"cat", "ls"} {
+		dest, err := os.Readlink(filepath.Join(dir, name))
+		if err != nil || dest != target {
+			t.Errorf("%s: expected link to %s, got %q (%v)", name, target, dest, err)
+		}
+	}
+	if _, err := os.Lstat(filepath.Join(dir, "secret")); err == nil {
+		t.Error("hidden commands should not be linked")
+	}
+
+	// Regular files are not overwritten
+	os.Remove(filepath.Join(dir, "cat"))
+	if err := os.WriteFile(filepath.Join(dir, "cat"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := root.CreateLinks(dir, target); err == nil {
+		t.Error("expected error when a regular file is in the way")
+	}
+}
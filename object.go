@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MethodDescriber can be implemented by objects passed to FromObject to
+// describe their methods. name is the Go method name; returning nil args
+// keeps the generated ones.
+type MethodDescriber interface {
+	DescribeMethod(name string) (description string, args []Argument)
+}
+
+// FromObject builds a command named after the object's type (kebab-cased)
+// with one subcommand per exported method of the form
+//
+//	func (s *Service) CreateUser(ctx context.Context, [cmd *cli.Command,] params...) [error]
+//
+// Subcommands are named after the kebab-cased method name. Parameters that
+// convertArgument supports become positional arguments (pointers are
+// optional, a variadic parameter takes the remaining arguments), and a struct
+// or struct pointer parameter is an options struct whose exported fields are
+// bound as flags: named by their `cli:"name,short"` tag or kebab-cased, with
+// optional `usage` and `default` tags, or skipped with `cli:"-"`.
+// Methods with any other signature are ignored.
+func FromObject(obj interface{}) *Command {
+	value := reflect.ValueOf(obj)
+	if !value.IsValid() || (value.Kind() == reflect.Ptr && value.IsNil()) {
+		panic("FromObject requires a non-nil object")
+	}
+
+	cmd := Cmd(kebabCase(reflect.Indirect(value).Type().Name()))
+	describer, _ := obj.(MethodDescriber)
+
+	for i := 0; i < value.NumMethod(); i++ {
+		method := value.Type().Method(i)
+		sub := methodCommand(method.Name, value.Method(i))
+		if sub == nil {
+			continue
+		}
+		if describer != nil {
+			description, args := describer.DescribeMethod(method.Name)
+			sub.Description(description)
+			if args != nil {
+				sub.args = args
+			}
+		}
+		cmd.AddCommand(sub)
+	}
+	return cmd
+}
+
+// methodParams classifies the parameters of a method after the context (and
+// optional *Command): the indexes of positional parameters and of the
+// options struct (-1 if none). ok is false if the method can't be a command.
+func methodParams(methodType reflect.Type) (positional []int, options int, ok bool) {
+	options = -1
+	if methodType.NumIn() == 0 || !contextType.AssignableTo(methodType.In(0)) {
+		return nil, -1, false
+	}
+	switch {
+	case methodType.NumOut() > 1:
+		return nil, -1, false
+	case methodType.NumOut() == 1 && methodType.Out(0) != errorType:
+		return nil, -1, false
+	}
+
+	for i := 1; i < methodType.NumIn(); i++ {
+		paramType := methodType.In(i)
+		variadic := methodType.IsVariadic() && i == methodType.NumIn()-1
+		switch {
+		case i == 1 && paramType == commandType:
+		case variadic && isConvertibleArgType(paramType.Elem()):
+			positional = append(positional, i)
+		case !variadic && isConvertibleArgType(paramType):
+			positional = append(positional, i)
+		case !variadic && options < 0 && isOptionsType(paramType):
+			options = i
+		default:
+			return nil, -1, false
+		}
+	}
+	return positional, options, true
+}
+
+// isOptionsType reports whether t is a struct or struct pointer usable as an
+// options struct
+func isOptionsType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// methodCommand builds the subcommand for a bound method, or returns nil if
+// the method doesn't have a command signature
+func methodCommand(name string, method reflect.Value) *Command {
+	methodType := method.Type()
+	positional, options, ok := methodParams(methodType)
+	if !ok {
+		return nil
+	}
+
+	cmd := Cmd(kebabCase(name))
+
+	var optionsPtr reflect.Value
+	if options >= 0 {
+		optionsType := methodType.In(options)
+		if optionsType.Kind() == reflect.Ptr {
+			optionsType = optionsType.Elem()
+		}
+		optionsPtr = reflect.New(optionsType)
+		bindOptions(cmd, optionsPtr)
+	}
+
+	// The action takes the positional parameters, so the usual conversion
+	// and argument checks apply
+	actionIn := []reflect.Type{contextType, commandType}
+	for n, i := range positional {
+		paramType := methodType.In(i)
+		actionIn = append(actionIn, paramType)
+
+		argName := fmt.Sprintf("arg%d", n+1)
+		if methodType.IsVariadic() && i == methodType.NumIn()-1 {
+			cmd.ArgVariadic(argName, "", false)
+		} else {
+			cmd.Arg(argName, "", paramType.Kind() != reflect.Ptr)
+		}
+	}
+	actionType := reflect.FuncOf(actionIn, []reflect.Type{errorType}, methodType.IsVariadic())
+
+	action := reflect.MakeFunc(actionType, func(in []reflect.Value) []reflect.Value {
+		callIn := make([]reflect.Value, methodType.NumIn())
+		callIn[0] = in[0]
+		if methodType.NumIn() > 1 && methodType.In(1) == commandType {
+			callIn[1] = in[1]
+		}
+		for n, i := range positional {
+			callIn[i] = in[n+2]
+		}
+		if options >= 0 {
+			if methodType.In(options).Kind() == reflect.Ptr {
+				callIn[options] = optionsPtr
+			} else {
+				callIn[options] = optionsPtr.Elem()
+			}
+		}
+
+		var results []reflect.Value
+		if methodType.IsVariadic() {
+			results = method.CallSlice(callIn)
+		} else {
+			results = method.Call(callIn)
+		}
+		if len(results) == 0 {
+			return []reflect.Value{reflect.Zero(errorType)}
+		}
+		return results
+	})
+
+	return cmd.Action(action.Interface())
+}
+
+// bindOptions binds the exported fields of an options struct as flags
+func bindOptions(cmd *Command, structPtr reflect.Value) {
+	structValue := structPtr.Elem()
+	structType := structValue.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldValue := structValue.Field(i)
+		if !fieldValue.CanSet() {
+			continue
+		}
+
+		name, shorthand := kebabCase(field.Name), ""
+		if tag := field.Tag.Get("cli"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			parts := strings.Split(tag, ",")
+			name = strings.TrimSpace(parts[0])
+			if len(parts) > 1 {
+				shorthand = strings.TrimSpace(parts[1])
+			}
+		}
+
+		var defaultValue interface{}
+		if defaultTag := field.Tag.Get("default"); defaultTag != "" {
+			defaultValue = parseDefaultValue(defaultTag, field.Type)
+		}
+		cmd.Flag(fieldValue.Addr().Interface(), name, shorthand, defaultValue, field.Tag.Get("usage"))
+	}
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type adminService struct {
+	calls []string
+}
+
+type CreateUserOptions struct {
+	Admin   bool          `usage:"Grant admin rights"`
+	Team    string        `cli:"team,t" default:"core"`
+	Expires time.Duration `default:"24h"`
+	Note    string        `cli:"-"`
+}
+
+func (s *adminService) CreateUser(ctx context.Context, name string, age int, opts CreateUserOptions) error {
+	s.calls = append(s.calls, strings.Join([]string{"create", name, strconv.Itoa(age), strconv.FormatBool(opts.Admin), opts.Team, opts.Expires.String()}, " "))
+	return nil
+}
+
+func (s *adminService) DeleteUsers(ctx context.Context, cmd *Command, force *bool, names ...string) error {
+	f := "nil"
+	if force != nil {
+		f = strconv.FormatBool(*force)
+	}
+	s.calls = append(s.calls, "delete "+cmd.GetName()+" "+f+" "+strings.Join(names, ","))
+	return nil
+}
+
+func (s *adminService) Fail(ctx context.Context) error {
+	return errors.New("boom")
+}
+
+func (s *adminService) Ping(ctx context.Context) {
+	s.calls = append(s.calls, "ping")
+}
+
+// Not commands: no context, unsupported parameter or result
+func (s *adminService) String() string                                  { return "admin" }
+func (s *adminService) Watch(ctx context.Context, ch chan string) error { return nil }
+func (s *adminService) Count(ctx context.Context) (int, error)          { return 0, nil }
+
+// TestFromObject tests exposing methods as subcommands
+func TestFromObject(t *testing.T) {
+	svc := &adminService{}
+	cmd := FromObject(svc)
+
+	if cmd.GetName() != "admin-service" {
+		t.Errorf("expected name 'admin-service', got %q", cmd.GetName())
+	}
+	var names []string
+	for name := range cmd.GetCommands() {
+		names = append(names, name)
+	}
+	for _, want := range []string{"create-user", "delete-users", "fail", "ping"} {
+		if !containsWord(names, want) {
+			t.Errorf("expected subcommand %q in %v", want, names)
+		}
+	}
+	for _, unwanted := range []string{"string", "watch", "count"} {
+		if containsWord(names, unwanted) {
+			t.Errorf("unexpected subcommand %q", unwanted)
+		}
+	}
+	if err := cmd.Validate(); err != nil {
+		t.Fatalf("generated commands should be valid: %v", err)
+	}
+
+	run := func(args ...string) error {
+		root := Root("app")
+		root.AddCommand(FromObject(svc))
+		return root.ExecuteWithArgs(append([]string{"admin-service"}, args...))
+	}
+
+	if err := run("create-user", "alice", "42", "--admin", "-t=ops"); err != nil {
+		t.Fatalf("create-user failed: %v", err)
+	}
+	if err := run("delete-users", "true", "bob", "carol"); err != nil {
+		t.Fatalf("delete-users failed: %v", err)
+	}
+	if err := run("delete-users"); err != nil {
+		t.Fatalf("delete-users without args failed: %v", err)
+	}
+	if err := run("ping"); err != nil {
+		t.Fatalf("ping failed: %v", err)
+	}
+
+	want := []string{
+		"create alice 42 true ops 24h0m0s",
+		"delete delete-users true bob,carol",
+		"delete delete-users nil ",
+		"ping",
+	}
+	if strings.Join(svc.calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected calls:\n%s", strings.Join(svc.calls, "\n"))
+	}
+
+	if err := run("fail"); err == nil || err.Error() != "boom" {
+		t.Errorf("expected method error, got %v", err)
+	}
+	if _, ok := run("create-user", "alice", "old").(*ArgumentError); !ok {
+		t.Error("expected conversion error for a non-numeric age")
+	}
+	if _, ok := run("create-user", "alice").(*ArgumentError); !ok {
+		t.Error("expected missing argument error")
+	}
+	if _, ok := run("create-user", "alice", "1", "--note=x").(*FlagError); !ok {
+		t.Error("fields tagged cli:\"-\" should not be flags")
+	}
+}
+
+type describedService struct{}
+
+func (describedService) Restart(ctx context.Context, service string, wait *time.Duration) error {
+	return nil
+}
+
+func (describedService) DescribeMethod(name string) (string, []Argument) {
+	if name == "Restart" {
+		return "Restart a service", []Argument{
+			{Name: "service", Description: "Service name", Required: true},
+			{Name: "wait", Description: "Time to wait", Default: "5s"},
+		}
+	}
+	return "", nil
+}
+
+// TestFromObjectDescriber tests method descriptions and argument metadata
+func TestFromObjectDescriber(t *testing.T) {
+	cmd := FromObject(describedService{})
+	restart, err := cmd.Find("restart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restart.GetDescription() != "Restart a service" {
+		t.Errorf("unexpected description %q", restart.GetDescription())
+	}
+	if args := restart.GetArgs(); len(args) != 2 || args[0].Name != "service" || args[1].Default != "5s" {
+		t.Errorf("unexpected args %+v", args)
+	}
+
+	output := captureStdout(t, func() { restart.ShowHelp() })
+	if !strings.Contains(output, "Service name") {
+		t.Errorf("expected argument descriptions in help:\n%s", output)
+	}
+}
@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Plugin is an external executable providing a subcommand, named after the
+// command path joined with dashes, e.g. "app-deploy" for "app deploy"
+type Plugin struct {
+	Name        string // Subcommand name, e.g. "deploy"
+	Path        string // Absolute path of the executable
+	Description string // From the plugin manifest, if any
+}
+
+// pluginManifest is read from "<executable>.json" next to a plugin
+type pluginManifest struct {
+	Description string `json:"description"`
+}
+
+// EnablePlugins makes unknown subcommands run external executables named
+// "<command path>-<name>" found in dirs or on PATH (dirs are searched first).
+// Set it on the root.
+func (c *Command) EnablePlugins(dirs ...string) *Command {
+	c.pluginsEnabled = true
+	c.pluginDirs = dirs
+	return c
+}
+
+// DisablePlugins turns off plugin discovery (default)
+func (c *Command) DisablePlugins() *Command {
+	c.pluginsEnabled = false
+	return c
+}
+
+// GetPlugins returns the plugins available as subcommands of c, sorted by
+// name. Plugins shadowed by a subcommand are omitted.
+func (c *Command) GetPlugins() []Plugin {
+	if !c.root().pluginsEnabled {
+		return nil
+	}
+
+	prefix := c.pluginPrefix()
+	found := make(map[string]Plugin)
+	for _, dir := range c.root().pluginSearchPath() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name(), prefix)
+			if !ok || name == "" {
+				continue
+			}
+			if _, seen := found[name]; seen {
+				continue
+			}
+			if c.lookupSubcommand(name) != nil {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			found[name] = newPlugin(name, path)
+		}
+	}
+
+	plugins := make([]Plugin, 0, len(found))
+	for _, plugin := range found {
+		plugins = append(plugins, plugin)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// findPlugin returns the plugin providing subcommand name of c, or nil
+func (c *Command) findPlugin(name string) *Plugin {
+	if !c.root().pluginsEnabled || name == "" || strings.ContainsAny(name, `/\`) {
+		return nil
+	}
+
+	base := c.pluginPrefix() + name
+	for _, dir := range c.root().pluginSearchPath() {
+		for _, candidate := range executableNames(base) {
+			path := filepath.Join(dir, candidate)
+			if isExecutable(path) {
+				plugin := newPlugin(name, path)
+				return &plugin
+			}
+		}
+	}
+	return nil
+}
+
+// runPlugin executes the plugin with args, exporting the resolved values of
+// c's flags as <ROOT>_FLAG_<NAME> environment variables
+func (c *Command) runPlugin(ctx context.Context, plugin *Plugin, args []string) error {
+	cmd := exec.CommandContext(ctx, plugin.Path, args...)
+	cmd.Stdin = c.stdinReader()
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = c.stderrWriter()
+	cmd.Env = append(os.Environ(), c.pluginEnv()...)
+
+	if err := cmd.Run(); err != nil {
+		pluginErr := &PluginError{
+			Name:     plugin.Name,
+			Path:     plugin.Path,
+			ExitCode: -1,
+			Err:      err,
+			Cmd:      c,
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			pluginErr.ExitCode = exitErr.ExitCode()
+		}
+		return pluginErr
+	}
+	return nil
+}
+
+// pluginEnv returns the flag values of c as environment variables
+func (c *Command) pluginEnv() []string {
+	prefix := envName(c.root().name) + "_FLAG_"
+	var env []string
+	for _, flag := range c.inheritedFlags().GetFlags() {
+		env = append(env, prefix+envName(flag.PrimaryName())+"="+flagEnvValue(flag))
+	}
+	return env
+}
+
+// flagEnvValue formats a flag value for the environment; slices are
+// joined with commas
+func flagEnvValue(flag *Flag) string {
+	value := flag.value
+	if value.Kind() == reflect.Slice {
+		parts := make([]string, value.Len())
+		for i := range parts {
+			parts[i] = fmt.Sprint(value.Index(i).Interface())
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// envName converts a name to an environment variable name, e.g. "dry-run" -> "DRY_RUN"
+func envName(name string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(name))
+}
+
+// pluginPrefix returns the executable name prefix for plugins of c
+func (c *Command) pluginPrefix() string {
+	return strings.ReplaceAll(c.getCommandPath(), " ", "-") + "-"
+}
+
+// pluginSearchPath returns the configured plugin dirs followed by PATH
+func (c *Command) pluginSearchPath() []string {
+	dirs := append([]string{}, c.pluginDirs...)
+	return append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+// newPlugin describes the plugin at path, reading its manifest if present
+func newPlugin(name, path string) Plugin {
+	plugin := Plugin{Name: name, Path: path}
+	manifestPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".json"
+	if runtime.GOOS != "windows" {
+		manifestPath = path + ".json"
+	}
+	if data, err := os.ReadFile(manifestPath); err == nil {
+		var manifest pluginManifest
+		if json.Unmarshal(data, &manifest) == nil {
+			plugin.Description = manifest.Description
+		}
+	}
+	return plugin
+}
+
+// pluginName extracts the subcommand name from an executable file name
+func pluginName(file, prefix string) (string, bool) {
+	if !strings.HasPrefix(file, prefix) || strings.HasSuffix(file, ".json") {
+		return "", false
+	}
+	name := strings.TrimPrefix(file, prefix)
+	if runtime.GOOS == "windows" {
+		ext := filepath.Ext(name)
+		if !strings.EqualFold(ext, ".exe") && !strings.EqualFold(ext, ".bat") && !strings.EqualFold(ext, ".cmd") {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name, true
+}
+
+// executableNames returns the file names an executable may have on this platform
+func executableNames(base string) []string {
+	if runtime.GOOS == "windows" {
+		return []string{base + ".exe", base + ".bat", base + ".cmd"}
+	}
+	return []string{base}
+}
+
+// isExecutable reports whether path is an executable regular file
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0111 != 0
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writePlugin creates an executable shell script in dir that records its
+// arguments and exported flags in a file next to it
+func writePlugin(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin tests use shell scripts")
+	}
+	path := filepath.Join(dir, name)
+	script := "#!/bin/sh\n" + body + "\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestPluginExecution tests running a plugin with args and exported flags
+func TestPluginExecution(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.txt")
+	writePlugin(t, dir, "app-hello", `echo "$@" > `+out+`; echo "$APP_FLAG_VERBOSE $APP_FLAG_REGION $APP_FLAG_TAG" >> `+out)
+	t.Setenv("PATH", "")
+
+	var verbose bool
+	var region string
+	var tags []string
+	root := Root("app").
+		EnablePlugins(dir).
+		Flag(&verbose, "verbose", "v", false, "Verbose").
+		Flag(&region, "region", "", "eu", "Region").
+		Flag(&tags, "tag", "", []string{}, "Tags")
+	root.AddCommand(Cmd("status"))
+
+	if err := root.ExecuteWithArgs([]string{"--verbose", "--tag=a", "--tag=b", "hello", "world", "--name=x"}); err != nil {
+		t.Fatalf("plugin execution failed: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "world --name=x\ntrue eu a,b\n" {
+		t.Errorf("unexpected plugin output: %q", got)
+	}
+}
+
+// TestPluginFailure tests that a failing plugin returns its exit code
+func TestPluginFailure(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "app-broken", "exit 3")
+	t.Setenv("PATH", dir)
+
+	root := Root("app").EnablePlugins()
+	root.AddCommand(Cmd("status"))
+
+	err := root.ExecuteWithArgs([]string{"broken"})
+	var pluginErr *PluginError
+	if !errors.As(err, &pluginErr) {
+		t.Fatalf("expected PluginError, got %T: %v", err, err)
+	}
+	if pluginErr.ExitCode != 3 || pluginErr.Name != "broken" {
+		t.Errorf("unexpected plugin error: %+v", pluginErr)
+	}
+}
+
+// TestPluginDiscovery tests listing plugins in help and completion
+func TestPluginDiscovery(t *testing.T) {
+	dir, pathDir := t.TempDir(), t.TempDir()
+	writePlugin(t, dir, "app-lint", "exit 0")
+	if err := os.WriteFile(filepath.Join(dir, "app-lint.json"), []byte(`{"description": "Lint the project"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writePlugin(t, pathDir, "app-fmt", "exit 0")
+	writePlugin(t, pathDir, "app-status", "exit 0") // shadowed by the real command
+	writePlugin(t, pathDir, "app-db-backup", "exit 0")
+	if err := os.WriteFile(filepath.Join(pathDir, "app-noexec"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", pathDir)
+
+	root := Root("app").EnablePlugins(dir)
+	root.AddCommand(Cmd("status"))
+	db := Cmd("db")
+	root.AddCommand(db)
+
+	plugins := root.GetPlugins()
+	var names []string
+	for _, plugin := range plugins {
+		names = append(names, plugin.Name)
+	}
+	if strings.Join(names, ",") != "db-backup,fmt,lint" {
+		t.Errorf("unexpected plugins: %v", names)
+	}
+	if plugins[2].Description != "Lint the project" {
+		t.Errorf("expected manifest description, got %q", plugins[2].Description)
+	}
+
+	if dbPlugins := db.GetPlugins(); len(dbPlugins) != 1 || dbPlugins[0].Name != "backup" {
+		t.Errorf("expected nested plugin backup, got %v", dbPlugins)
+	}
+
+	words := getCompletionWords(root, nil)
+	if !containsWord(words, "lint") || !containsWord(words, "fmt") {
+		t.Errorf("expected plugins in completion, got %v", words)
+	}
+
+	out := captureStdout(t, root.ShowHelp)
+	if !strings.Contains(out, "Plugins") || !strings.Contains(out, "Lint the project") {
+		t.Errorf("expected plugins in help:\n%s", out)
+	}
+
+	root.DisablePlugins()
+	if len(root.GetPlugins()) != 0 {
+		t.Error("plugins should not be discovered when disabled")
+	}
+	if _, ok := root.ExecuteWithArgs([]string{"lint"}).(*CommandNotFoundError); !ok {
+		t.Error("expected CommandNotFoundError when plugins are disabled")
+	}
+}
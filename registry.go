@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// registration is a command registered with Register
+type registration struct {
+	path  []string // Full command path below the root, ending with the command name
+	cmd   *Command
+	order int      // Ordering hint among siblings (lower first)
+	seq   int      // Registration sequence, breaking ties
+	root  *Command // Root the command was mounted into (nil = not mounted yet)
+}
+
+// registry holds commands registered by packages, typically from init()
+var registry struct {
+	mu      sync.Mutex
+	entries []*registration
+}
+
+// RegisterOption configures a registration
+type RegisterOption func(*registration)
+
+// WithOrder sets an ordering hint: registered siblings are added in
+// ascending order, then in registration order. It decides their position
+// when the root uses OrderDeclaration.
+func WithOrder(order int) RegisterOption {
+	return func(r *registration) {
+		r.order = order
+	}
+}
+
+// Register registers cmd under a command path below the root, e.g.
+// Register("db backup", backupCmd) from a package's init(). The last path
+// element must be the command's name. Registered commands are added to the
+// tree of the root that uses the registry when it is executed (see
+// UseRegistry), or by MountRegistry.
+func Register(path string, cmd *Command, opts ...RegisterOption) {
+	parts := strings.Fields(path)
+	if len(parts) == 0 || parts[len(parts)-1] != cmd.name {
+		panic(fmt.Sprintf("cannot register command '%s' at path %q: the path must end with the command name", cmd.name, path))
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	entry := &registration{path: parts, cmd: cmd, seq: len(registry.entries)}
+	for _, opt := range opts {
+		opt(entry)
+	}
+	registry.entries = append(registry.entries, entry)
+}
+
+// UseRegistry makes root c the tree that commands registered with Register
+// are mounted into: Execute, AddCompletion and the shell mount them first.
+// Roots that don't use the registry ignore it.
+func (c *Command) UseRegistry() *Command {
+	c.useRegistry = true
+	return c
+}
+
+// MountRegistry adds the commands registered with Register to the tree of
+// root c, parents before children. Commands not mounted yet are added; a
+// missing parent, a name collision or a command already mounted into
+// another root is reported as a RegistryError, and the command stays
+// pending so the error is reported again by the next call.
+func (c *Command) MountRegistry() error {
+	var errs []error
+	var pending []*registration
+
+	registry.mu.Lock()
+	for _, entry := range registry.entries {
+		switch entry.root {
+		case nil:
+			pending = append(pending, entry)
+		case c:
+		default:
+			errs = append(errs, &RegistryError{
+				Path: strings.Join(entry.path, " "),
+				Msg:  fmt.Sprintf("already mounted into root '%s'", entry.root.name),
+			})
+		}
+	}
+	registry.mu.Unlock()
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		a, b := pending[i], pending[j]
+		if len(a.path) != len(b.path) {
+			return len(a.path) < len(b.path)
+		}
+		if a.order != b.order {
+			return a.order < b.order
+		}
+		return a.seq < b.seq
+	})
+
+	for _, entry := range pending {
+		if err := c.mount(entry); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		registry.mu.Lock()
+		entry.root = c
+		registry.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// mountRegistry mounts registered commands if c is a root using the registry
+func (c *Command) mountRegistry() error {
+	if c.parent != nil || !c.useRegistry {
+		return nil
+	}
+	return c.MountRegistry()
+}
+
+// mount adds a single registered command below root c
+func (c *Command) mount(entry *registration) error {
+	path := strings.Join(entry.path, " ")
+
+	parentPath := entry.path[:len(entry.path)-1]
+	parent, err := c.Find(parentPath...)
+	if err != nil {
+		return &RegistryError{
+			Path: path,
+			Msg:  fmt.Sprintf("parent '%s' does not exist", strings.Join(parentPath, " ")),
+		}
+	}
+
+	if clash := parent.nameClash(entry.cmd, nil); clash != "" {
+		return &RegistryError{Path: path, Msg: clash}
+	}
+	parent.AddCommand(entry.cmd)
+	return nil
+}
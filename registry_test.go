@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// useEmptyRegistry isolates a test from commands registered elsewhere
+func useEmptyRegistry(t *testing.T) {
+	t.Helper()
+	registry.mu.Lock()
+	saved := registry.entries
+	registry.entries = nil
+	registry.mu.Unlock()
+
+	t.Cleanup(func() {
+		registry.mu.Lock()
+		registry.entries = saved
+		registry.mu.Unlock()
+	})
+}
+
+// TestRegisterAssemblesTree tests mounting registered commands on execute
+func TestRegisterAssemblesTree(t *testing.T) {
+	useEmptyRegistry(t)
+
+	var ran string
+	// Children may be registered before their parents
+	Register("db backup create", Cmd("create").Action(func(ctx context.Context, c *Command) error {
+		ran = c.getCommandPath()
+		return nil
+	}))
+	Register("db backup", Cmd("backup"))
+	Register("db", Cmd("db"))
+
+	root := Root("app").UseRegistry()
+	if err := root.ExecuteWithArgs([]string{"db", "backup", "create"}); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if ran != "app db backup create" {
+		t.Errorf("expected registered command to run, got %q", ran)
+	}
+
+	// Mounting again adds nothing new
+	if err := root.MountRegistry(); err != nil {
+		t.Errorf("second mount failed: %v", err)
+	}
+}
+
+// TestRegisterOrdering tests ordering hints for registered siblings
+func TestRegisterOrdering(t *testing.T) {
+	useEmptyRegistry(t)
+
+	Register("zeta", Cmd("zeta"))
+	Register("beta", Cmd("beta"), WithOrder(-1))
+	Register("alpha", Cmd("alpha"), WithOrder(5))
+	Register("gamma", Cmd("gamma"))
+
+	root := Root("app").OrderCommands(OrderDeclaration)
+	if err := root.MountRegistry(); err != nil {
+		t.Fatalf("mount failed: %v", err)
+	}
+
+	var names []string
+	for _, cmd := range root.sortedSubcommands() {
+		names = append(names, cmd.GetName())
+	}
+	if got := strings.Join(names, ","); got != "beta,zeta,gamma,alpha" {
+		t.Errorf("unexpected order: %s", got)
+	}
+}
+
+// TestRegisterErrors tests missing parents and collisions
+func TestRegisterErrors(t *testing.T) {
+	useEmptyRegistry(t)
+
+	Register("tools lint", Cmd("lint"))
+	Register("status", Cmd("status"))
+	Register("stat", Cmd("stat"))
+
+	root := Root("app").UseRegistry()
+	root.AddCommand(Cmd("status"))
+	root.AddCommand(Cmd("info").Aliases("stat"))
+
+	err := root.ExecuteWithArgs([]string{"status"})
+	if err == nil {
+		t.Fatal("expected registry errors")
+	}
+	var regErr *RegistryError
+	if !errors.As(err, &regErr) {
+		t.Fatalf("expected RegistryError, got %T: %v", err, err)
+	}
+	for _, want := range []string{"'tools lint': parent 'tools' does not exist", "'status': 'status' is already used", "'stat': 'stat' is already used"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic when the path does not end with the command name")
+		}
+	}()
+	Register("db dump", Cmd("backup"))
+}
+
+// TestRegistryErrorsReportedAgain tests that failed registrations stay pending
+func TestRegistryErrorsReportedAgain(t *testing.T) {
+	useEmptyRegistry(t)
+
+	Register("tools lint", Cmd("lint"))
+	root := Root("app").UseRegistry()
+	root.AddCommand(Cmd("status"))
+	AddCompletion(root) // mounts and ignores the error
+
+	for i := 0; i < 2; i++ {
+		var regErr *RegistryError
+		if err := root.ExecuteWithArgs([]string{"status"}); !errors.As(err, &regErr) {
+			t.Fatalf("run %d: expected RegistryError, got %v", i, err)
+		}
+	}
+
+	// Once the parent exists, the pending command is mounted
+	root.AddCommand(Cmd("tools"))
+	if err := root.MountRegistry(); err != nil {
+		t.Fatalf("mount failed: %v", err)
+	}
+	if _, err := root.Find("tools lint"); err != nil {
+		t.Errorf("expected 'tools lint' to be mounted: %v", err)
+	}
+}
+
+// TestRegistryOptIn tests that only roots using the registry mount it
+func TestRegistryOptIn(t *testing.T) {
+	useEmptyRegistry(t)
+
+	Register("db backup", Cmd("backup"))
+
+	// Other roots, e.g. built in unit tests, are not affected
+	ran := false
+	other := Root("other").Action(func(ctx context.Context, c *Command) error {
+		ran = true
+		return nil
+	})
+	if err := other.ExecuteWithArgs(nil); err != nil || !ran {
+		t.Fatalf("root without the registry should run, got %v", err)
+	}
+
+	Register("db", Cmd("db"))
+	app := Root("app").UseRegistry()
+	if err := app.MountRegistry(); err != nil {
+		t.Fatalf("mount failed: %v", err)
+	}
+
+	// Registered commands are not moved to another root
+	second := Root("second").UseRegistry()
+	var regErr *RegistryError
+	if err := second.MountRegistry(); !errors.As(err, &regErr) || !strings.Contains(err.Error(), "already mounted into root 'app'") {
+		t.Errorf("expected already mounted error, got %v", err)
+	}
+	if backup, err := app.Find("db backup"); err != nil || backup.root() != app {
+		t.Errorf("'db backup' should stay in app: %v", err)
+	}
+}
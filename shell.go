@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxShellHistory is the number of history lines loaded from the history file
+const maxShellHistory = 1000
+
+// shellContextKey marks the context of commands run from the shell
+type shellContextKey struct{}
+
+// Shell registers a "shell" subcommand on the root that starts an interactive
+// shell over stdin and stdout (see RunShell), unless a shell command exists
+func (c *Command) Shell() *Command {
+	if c.lookupSubcommand("shell") != nil {
+		return c
+	}
+	shellCmd := Cmd("shell").
+		Description("Start an interactive shell").
+		Action(func(ctx context.Context, cmd *Command) error {
+			return cmd.RunShell(ctx, os.Stdin, os.Stdout)
+		})
+	shellCmd.builtin = true
+	c.AddCommand(shellCmd)
+	return c
+}
+
+// ShellPrompt sets the prompt of the interactive shell (default: "<name>> ")
+func (c *Command) ShellPrompt(prompt string) *Command {
+	c.shellPrompt = prompt
+	return c
+}
+
+// ShellHistory sets the file where the shell history is kept
+// (default: ~/.<name>_history). An empty path keeps history in memory only.
+func (c *Command) ShellHistory(path string) *Command {
+	c.shellHistory = path
+	c.shellHistorySet = true
+	return c
+}
+
+// RunShell runs an interactive shell over the root's command tree: each line
+// is split like ExecuteLine and executed like the arguments of the program,
+// with flags reset to their initial values first. Lines are read from in
+// with line editing (on a terminal), history and tab completion; the prompt,
+// completion lists, errors, help and version output are written to out.
+// "help [command]" works as on the command line, unless help is disabled.
+// The built-in "exit" (or "quit") ends the shell, and so does end of input.
+func (c *Command) RunShell(ctx context.Context, in io.Reader, out io.Writer) error {
+	root := c.root()
+	if ctx.Value(shellContextKey{}) != nil {
+		return fmt.Errorf("already running in a %s shell", root.name)
+	}
+	ctx = context.WithValue(ctx, shellContextKey{}, root)
+
+	// Help and version output goes to out, like the prompt
+	root.shellOut = out
+	defer func() { root.shellOut = nil }()
+
+	// Include registered commands, so they can be completed before the first line
+	if err := root.mountRegistry(); err != nil {
+		return err
+	}
+
+	prompt := root.shellPrompt
+	if prompt == "" {
+		prompt = root.name + "> "
+	}
+	historyFile := root.shellHistoryFile()
+
+	editor := newLineEditor(in, out, root.shellCompletions)
+	editor.history = loadShellHistory(historyFile)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line, err := editor.readLine(prompt)
+		switch {
+		case err == errInterrupted:
+			continue
+		case err == io.EOF:
+			return nil
+		case err != nil:
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		editor.addHistory(line)
+		appendShellHistory(historyFile, line)
+
+		args, err := root.splitLine(line)
+		if err != nil {
+			FprintError(out, err)
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		switch {
+		case args[0] == "exit" || args[0] == "quit":
+			return nil
+		default:
+			root.resetFlags()
+			err = root.execute(ctx, args)
+		}
+		if err != nil {
+			FprintError(out, err)
+		}
+	}
+}
+
+// stdoutWriter returns the writer for help and version output: the output
+// of the running shell, or os.Stdout
+func (c *Command) stdoutWriter() io.Writer {
+	if out := c.root().shellOut; out != nil {
+		return out
+	}
+	return os.Stdout
+}
+
+// shellHistoryFile returns the history file of the shell, or "" for none
+func (c *Command) shellHistoryFile() string {
+	if c.shellHistorySet {
+		return c.shellHistory
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, "."+c.name+"_history")
+}
+
+// shellCompletions completes the last word of a shell line, using the same
+// words as shell completion scripts. Flags are only offered for words
+// starting with "-".
+func (c *Command) shellCompletions(line string) (string, []string) {
+	words := strings.Fields(line)
+	word := ""
+	if len(words) > 0 && !strings.HasSuffix(line, " ") {
+		word = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	// Resolve the command the word belongs to
+	cmd, positional := completionTarget(c, words)
+
+	var candidates []string
+	seen := make(map[string]bool)
+	add := func(candidate string) {
+		if !seen[candidate] && strings.HasPrefix(candidate, word) {
+			seen[candidate] = true
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	for _, candidate := range getCompletionWords(cmd, positional) {
+		switch {
+		case candidate == completeFilesDirective || candidate == completeDirsDirective:
+		case strings.HasPrefix(candidate, "__"):
+		case strings.HasPrefix(candidate, "-") != strings.HasPrefix(word, "-"):
+		case cmd == c && candidate == "shell":
+		default:
+			add(candidate)
+		}
+	}
+	if cmd == c && len(positional) == 0 && !strings.HasPrefix(word, "-") {
+		add("exit")
+	}
+	return word, candidates
+}
+
+// loadShellHistory reads the last lines of the history file
+func loadShellHistory(path string) []string {
+	if path == "" {
+		return nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var history []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			history = append(history, line)
+		}
+	}
+	if len(history) > maxShellHistory {
+		history = history[len(history)-maxShellHistory:]
+	}
+	return history
+}
+
+// appendShellHistory adds a line to the history file. History is best
+// effort: failures to write it are ignored.
+func appendShellHistory(path, line string) {
+	if path == "" {
+		return
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	fmt.Fprintln(file, line)
+}
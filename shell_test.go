@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newShellTestRoot returns a root with deploy and delete commands recording their runs
+func newShellTestRoot(calls *[]string) *Command {
+	var env string
+	root := Root("app").ShellHistory("")
+	root.AddCommand(Cmd("deploy").
+		Description("Deploy a service").
+		Flag(&env, "env", "e", "dev", "Environment").
+		Arg("service", "Service name", true).
+		Action(func(ctx context.Context, cmd *Command, service string) error {
+			*calls = append(*calls, "deploy "+env+" "+service)
+			return nil
+		}))
+	root.AddCommand(Cmd("delete").
+		Action(func(ctx context.Context, cmd *Command) error {
+			*calls = append(*calls, "delete")
+			return nil
+		}))
+	return root.Shell()
+}
+
+// TestRunShell tests executing lines with fresh flag state and the built-ins
+func TestRunShell(t *testing.T) {
+	var calls []string
+	root := newShellTestRoot(&calls)
+	historyFile := filepath.Join(t.TempDir(), "history")
+	root.ShellHistory(historyFile)
+
+	input := "deploy --env=prod api\n\ndeploy web\nnope\nshell\nhelp deploy\nexit\ndeploy never\n"
+	var out bytes.Buffer
+	stdout := captureStdout(t, func() {
+		if err := root.RunShell(context.Background(), strings.NewReader(input), &out); err != nil {
+			t.Errorf("RunShell failed: %v", err)
+		}
+	})
+	if stdout != "" {
+		t.Errorf("shell output should go to out, got on stdout:\n%s", stdout)
+	}
+
+	if strings.Join(calls, "|") != "deploy prod api|deploy dev web" {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+	if !strings.HasPrefix(out.String(), "app> ") {
+		t.Errorf("expected prompt, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "Error: unknown command") {
+		t.Errorf("expected unknown command error, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "already running in a app shell") {
+		t.Errorf("expected nested shell error, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "Deploy a service") {
+		t.Errorf("expected help for deploy, got:\n%s", out.String())
+	}
+
+	history, err := os.ReadFile(historyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "deploy --env=prod api\ndeploy web\nnope\nshell\nhelp deploy\nexit\n"
+	if string(history) != want {
+		t.Errorf("unexpected history file:\n%s", history)
+	}
+}
+
+// TestRunShellHistory tests recalling lines from the history file
+func TestRunShellHistory(t *testing.T) {
+	var calls []string
+	root := newShellTestRoot(&calls)
+	historyFile := filepath.Join(t.TempDir(), "history")
+	if err := os.WriteFile(historyFile, []byte("deploy --env=qa old\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	root.ShellHistory(historyFile)
+
+	// Up arrow recalls the file entry, Ctrl-P twice goes back past "delete"
+	input := "\x1b[A\ndelete\n\x10\x10\n"
+	root.RunShell(context.Background(), strings.NewReader(input), io.Discard)
+
+	if strings.Join(calls, "|") != "deploy qa old|delete|deploy qa old" {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+}
+
+// TestRunShellPipe tests running over a non-terminal file such as a pipe
+func TestRunShellPipe(t *testing.T) {
+	var calls []string
+	root := newShellTestRoot(&calls)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	w.WriteString("delete\n")
+	w.Close()
+
+	if err := root.RunShell(context.Background(), r, io.Discard); err != nil {
+		t.Fatalf("RunShell failed: %v", err)
+	}
+	if len(calls) != 1 || calls[0] != "delete" {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+}
+
+// TestRunShellStdin tests that commands reading stdin get the input after
+// their line
+func TestRunShellStdin(t *testing.T) {
+	input := strings.NewReader("load -\r\npayload\n")
+	var content string
+	root := Root("app").ShellHistory("").SetStdin(input)
+	root.AddCommand(Cmd("load").
+		Arg("input", "Input file", true).
+		Action(func(ctx context.Context, cmd *Command, r io.Reader) error {
+			data, err := io.ReadAll(r)
+			content = string(data)
+			return err
+		}))
+
+	if err := root.RunShell(context.Background(), input, io.Discard); err != nil {
+		t.Fatalf("RunShell failed: %v", err)
+	}
+	if content != "payload\n" {
+		t.Errorf("expected the rest of the input, got %q", content)
+	}
+}
+
+// TestShellCompletions tests completing words of a shell line
+func TestShellCompletions(t *testing.T) {
+	var calls []string
+	root := newShellTestRoot(&calls)
+	AddCompletion(root)
+
+	tests := []struct {
+		line string
+		word string
+		want []string
+	}{
+		{"dep", "dep", []string{"deploy"}},
+		{"de", "de", []string{"delete", "deploy"}},
+		{"deploy --e", "--e", []string{"--env"}},
+		{"deploy -", "-", []string{"--env", "-e"}},
+		{"", "", []string{"delete", "deploy", "help", "exit"}},
+	}
+	for _, tt := range tests {
+		word, got := root.shellCompletions(tt.line)
+		if word != tt.word || strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("shellCompletions(%q) = %q %v, want %q %v", tt.line, word, got, tt.word, tt.want)
+		}
+	}
+
+	// Tab completes a unique word and lists ambiguous ones
+	var out bytes.Buffer
+	root.RunShell(context.Background(), strings.NewReader("dep\tapi\nde\t\x15\n"), &out)
+	if len(calls) != 1 || calls[0] != "deploy dev api" {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+	if !strings.Contains(out.String(), "\ndelete  deploy\napp> de") {
+		t.Errorf("expected candidate list, got:\n%q", out.String())
+	}
+}
+
+// TestLineEditorKeys tests the editing keys
+func TestLineEditorKeys(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"abc\x02\x02X\n", "aXbc"},
+		{"hello world\x17\n", "hello "},
+		{"abc\x01\x0bxyz\n", "xyz"},
+		{"abc\x02\x15\n", "c"},
+		{"ab\x7fc\n", "ac"},
+		{"abc\x1b[D\x1b[3~\n", "ab"},
+		{"abc\x1b[H\x1b[C\x04\n", "ac"},
+		{"ab\x01\x1b[FZ\r\n", "abZ"},
+		{"no newline", "no newline"},
+	}
+	for _, tt := range tests {
+		editor := newLineEditor(strings.NewReader(tt.input), io.Discard, nil)
+		got, err := editor.readLine("> ")
+		if err != nil || got != tt.want {
+			t.Errorf("readLine(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+
+	editor := newLineEditor(strings.NewReader("abc\x03\x04"), io.Discard, nil)
+	if _, err := editor.readLine("> "); err != errInterrupted {
+		t.Errorf("expected errInterrupted, got %v", err)
+	}
+	if _, err := editor.readLine("> "); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+// TestRunShellQuoting tests that shell lines are split with shell quoting
+func TestRunShellQuoting(t *testing.T) {
+	var calls []string
+	root := newShellTestRoot(&calls)
+
+	var out bytes.Buffer
+	root.RunShell(context.Background(), strings.NewReader("deploy 'my api' # comment\ndeploy \"web\n# only a comment\n"), &out)
+
+	if len(calls) != 1 || calls[0] != "deploy dev my api" {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+	if !strings.Contains(out.String(), "Error: unterminated double quote at column 8") {
+		t.Errorf("expected line error, got:\n%s", out.String())
+	}
+}
+
+// TestRunShellHelpDisabled tests that the shell's help follows DisableHelp
+func TestRunShellHelpDisabled(t *testing.T) {
+	var calls []string
+	root := newShellTestRoot(&calls).DisableHelp()
+
+	var out bytes.Buffer
+	root.RunShell(context.Background(), strings.NewReader("help\nhelp deploy\n"), &out)
+	if strings.Contains(out.String(), "Deploy a service") || strings.Contains(out.String(), "Usage") {
+		t.Errorf("help should not be shown when disabled:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "Error: unknown command 'help'") {
+		t.Errorf("expected unknown command error, got:\n%s", out.String())
+	}
+	if _, candidates := root.shellCompletions("he"); len(candidates) != 0 {
+		t.Errorf("help should not be completed when disabled, got %v", candidates)
+	}
+}
@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// CommandSpec declares a command surface: everything but the handlers,
+// which are bound by name when the spec is loaded
+type CommandSpec struct {
+	Name           string         `json:"name"`
+	Description    string         `json:"description,omitempty"`
+	Aliases        []string       `json:"aliases,omitempty"`
+	Group          string         `json:"group,omitempty"`
+	Groups         []CommandGroup `json:"groups,omitempty"`
+	Hidden         bool           `json:"hidden,omitempty"`
+	Deprecated     string         `json:"deprecated,omitempty"`
+	Experimental   bool           `json:"experimental,omitempty"`
+	Handler        string         `json:"handler,omitempty"`
+	Flags          []FlagSpec     `json:"flags,omitempty"`
+	Args           []Argument     `json:"args,omitempty"`
+	DefaultCommand string         `json:"defaultCommand,omitempty"`
+	Commands       []CommandSpec  `json:"commands,omitempty"`
+}
+
+// FlagSpec declares a flag. Type is one of string, bool, int, uint, float,
+// duration or strings (a string slice, with a comma-separated default).
+type FlagSpec struct {
+	Name     string `json:"name"`
+	Short    string `json:"short,omitempty"`
+	Type     string `json:"type,omitempty"` // Default: string
+	Default  string `json:"default,omitempty"`
+	Usage    string `json:"usage,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	Hidden   bool   `json:"hidden,omitempty"`
+}
+
+// specFlagTypes maps FlagSpec types to Go types
+var specFlagTypes = map[string]reflect.Type{
+	"string":   reflect.TypeOf(""),
+	"bool":     reflect.TypeOf(false),
+	"int":      reflect.TypeOf(0),
+	"uint":     reflect.TypeOf(uint(0)),
+	"float":    reflect.TypeOf(float64(0)),
+	"duration": reflect.TypeOf(time.Duration(0)),
+	"strings":  reflect.TypeOf([]string{}),
+}
+
+// LoadSpec builds a command tree from a JSON spec, binding each command's
+// handler by name from handlers (action functions as accepted by Action).
+// Flag values are read in handlers with GetFlag. Unknown handlers, name
+// collisions and invalid definitions are reported together.
+func LoadSpec(data []byte, handlers map[string]interface{}) (*Command, error) {
+	var spec CommandSpec
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, &SpecError{Msg: err.Error()}
+	}
+
+	var errs []error
+	root := buildFromSpec(spec, "", handlers, &errs)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if err := root.Validate(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// LoadSpecFile reads a JSON spec file and builds its command tree with LoadSpec
+func LoadSpecFile(path string, handlers map[string]interface{}) (*Command, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return LoadSpec(data, handlers)
+}
+
+// buildFromSpec builds a command and its subcommands, collecting problems in errs
+func buildFromSpec(spec CommandSpec, parentPath string, handlers map[string]interface{}, errs *[]error) *Command {
+	path := strings.TrimSpace(parentPath + " " + spec.Name)
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, &SpecError{Path: path, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	if spec.Name == "" {
+		fail("command name is required")
+	}
+
+	cmd := Cmd(spec.Name).
+		Description(spec.Description).
+		Aliases(spec.Aliases...).
+		Group(spec.Group).
+		DefaultCommand(spec.DefaultCommand)
+	for _, group := range spec.Groups {
+		cmd.AddGroup(group.ID, group.Title)
+	}
+	if spec.Hidden {
+		cmd.Hidden()
+	}
+	if spec.Deprecated != "" {
+		cmd.Deprecated(spec.Deprecated)
+	}
+	if spec.Experimental {
+		cmd.Experimental()
+	}
+
+	for _, flagSpec := range spec.Flags {
+		if err := addSpecFlag(cmd, flagSpec); err != nil {
+			fail("flag '%s': %v", flagSpec.Name, err)
+		}
+	}
+	for _, arg := range spec.Args {
+		cmd.AddArg(arg)
+	}
+
+	if spec.Handler != "" {
+		handler, ok := handlers[spec.Handler]
+		if !ok {
+			fail("handler %q is not registered", spec.Handler)
+		} else {
+			cmd.Action(handler)
+			cmd.handler = spec.Handler
+		}
+	}
+
+	for _, childSpec := range spec.Commands {
+		child := buildFromSpec(childSpec, path, handlers, errs)
+		if clash := cmd.nameClash(child, nil); clash != "" {
+			fail("cannot add command '%s': %s", childSpec.Name, clash)
+			continue
+		}
+		cmd.AddCommand(child)
+	}
+	return cmd
+}
+
+// addSpecFlag allocates a variable for a declared flag and adds it to cmd
+func addSpecFlag(cmd *Command, spec FlagSpec) error {
+	if spec.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	typeName := spec.Type
+	if typeName == "" {
+		typeName = "string"
+	}
+	flagType, ok := specFlagTypes[typeName]
+	if !ok {
+		return fmt.Errorf("unsupported type %q", spec.Type)
+	}
+
+	var defaultValue interface{}
+	if spec.Default != "" {
+		if flagType.Kind() == reflect.Slice {
+			defaultValue = strings.Split(spec.Default, ",")
+		} else {
+			value, err := parseWithFlagSet(spec.Default, flagType)
+			if err != nil {
+				return fmt.Errorf("invalid default %q: %v", spec.Default, err)
+			}
+			defaultValue = value.Interface()
+		}
+	}
+
+	ptr := reflect.New(flagType).Interface()
+	switch {
+	case spec.Required:
+		cmd.FlagRequired(ptr, spec.Name, spec.Short, defaultValue, spec.Usage)
+	case spec.Hidden:
+		cmd.FlagHidden(ptr, spec.Name, spec.Short, defaultValue, spec.Usage)
+	default:
+		cmd.Flag(ptr, spec.Name, spec.Short, defaultValue, spec.Usage)
+	}
+	if flag := cmd.flags.GetFlag(spec.Name); flag != nil {
+		flag.hidden = spec.Hidden
+	}
+	return nil
+}
+
+// Spec describes the command tree in the spec format, so that it can be
+// exported and loaded again. Internal commands (named "__...") and built-in
+// commands (version, shell) are omitted, as they are added again by calling
+// Version or Shell on the loaded tree. Lazy commands are built.
+func (c *Command) Spec() (CommandSpec, error) {
+	spec := CommandSpec{
+		Name:           c.name,
+		Description:    c.description,
+		Aliases:        c.aliases,
+		Group:          c.group,
+		Groups:         c.groups,
+		Hidden:         c.hidden,
+		Deprecated:     c.deprecated,
+		Experimental:   c.experimental,
+		Handler:        c.handler,
+		Args:           c.args,
+		DefaultCommand: c.defaultCommand,
+	}
+
+	for _, flag := range c.flags.GetFlags() {
+		flagSpec, err := specForFlag(flag)
+		if err != nil {
+			return CommandSpec{}, &SpecError{Path: c.getCommandPath(), Msg: err.Error()}
+		}
+		spec.Flags = append(spec.Flags, flagSpec)
+	}
+
+	for _, cmd := range c.sortedSubcommands() {
+		if strings.HasPrefix(cmd.name, "__") || cmd.builtin {
+			continue
+		}
+		childSpec, err := cmd.materialize().Spec()
+		if err != nil {
+			return CommandSpec{}, err
+		}
+		spec.Commands = append(spec.Commands, childSpec)
+	}
+	return spec, nil
+}
+
+// ExportSpec returns the command tree as an indented JSON spec
+func (c *Command) ExportSpec() ([]byte, error) {
+	spec, err := c.Spec()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// specForFlag describes a flag in the spec format
+func specForFlag(flag *Flag) (FlagSpec, error) {
+	spec := FlagSpec{
+		Name:     flag.PrimaryName(),
+		Short:    flag.ShortName(),
+		Usage:    flag.GetUsage(),
+		Required: flag.IsRequired(),
+		Hidden:   flag.IsHidden(),
+	}
+
+	// Only the exact types a spec can load round-trip: custom, Set-based and
+	// path types would come back as plain values without their behavior
+	for name, flagType := range specFlagTypes {
+		if flag.flagType == flagType {
+			spec.Type = name
+		}
+	}
+	switch spec.Type {
+	case "":
+		return FlagSpec{}, fmt.Errorf("flag '%s': unsupported type %s", spec.Name, flag.flagType)
+	case "string":
+		spec.Type = ""
+	}
+
+	if def := flag.GetDefault(); def != nil {
+		value := reflect.ValueOf(def)
+		if value.Kind() == reflect.Slice {
+			parts := make([]string, value.Len())
+			for i := range parts {
+				parts[i] = fmt.Sprint(value.Index(i).Interface())
+			}
+			spec.Default = strings.Join(parts, ",")
+		} else if !value.IsZero() {
+			spec.Default = fmt.Sprint(def)
+		}
+	}
+	return spec, nil
+}
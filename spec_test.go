@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSpec = `{
+  "name": "app",
+  "description": "Platform tools",
+  "groups": [{"id": "core", "title": "Core"}],
+  "flags": [
+    {"name": "verbose", "short": "v", "type": "bool", "usage": "Verbose output"}
+  ],
+  "commands": [
+    {
+      "name": "deploy",
+      "description": "Deploy a service",
+      "aliases": ["d"],
+      "group": "core",
+      "handler": "deploy",
+      "flags": [
+        {"name": "timeout", "type": "duration", "default": "30s", "usage": "Timeout"},
+        {"name": "tag", "type": "strings", "default": "a,b"},
+        {"name": "token", "required": true, "hidden": true}
+      ],
+      "args": [
+        {"name": "env", "required": true, "choices": ["dev", "prod"]},
+        {"name": "replicas", "default": "1", "type": "int"}
+      ]
+    },
+    {"name": "legacy", "deprecated": "use deploy", "hidden": true}
+  ]
+}`
+
+// TestLoadSpec tests building and running a tree from a spec
+func TestLoadSpec(t *testing.T) {
+	var gotEnv string
+	var gotReplicas int
+	var gotTimeout time.Duration
+	var gotVerbose bool
+	var gotTags []string
+	handlers := map[string]interface{}{
+		"deploy": func(ctx context.Context, cmd *Command, env string, replicas int) error {
+			gotEnv, gotReplicas = env, replicas
+			gotTimeout = cmd.GetFlag("timeout").GetValue().(time.Duration)
+			gotVerbose = cmd.GetFlag("verbose").GetValue().(bool)
+			gotTags = cmd.GetFlag("tag").GetValue().([]string)
+			return nil
+		},
+	}
+
+	root, err := LoadSpec([]byte(testSpec), handlers)
+	if err != nil {
+		t.Fatalf("LoadSpec failed: %v", err)
+	}
+
+	if err := root.ExecuteWithArgs([]string{"-v", "d", "prod", "--token=x"}); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if gotEnv != "prod" || gotReplicas != 1 || gotTimeout != 30*time.Second || !gotVerbose {
+		t.Errorf("unexpected values: %q %d %v %v", gotEnv, gotReplicas, gotTimeout, gotVerbose)
+	}
+	if strings.Join(gotTags, ",") != "a,b" {
+		t.Errorf("unexpected tags: %v", gotTags)
+	}
+
+	fresh, _ := LoadSpec([]byte(testSpec), handlers)
+	if _, ok := fresh.ExecuteWithArgs([]string{"deploy", "prod"}).(*FlagError); !ok {
+		t.Error("expected required flag error")
+	}
+	fresh, _ = LoadSpec([]byte(testSpec), handlers)
+	if _, ok := fresh.ExecuteWithArgs([]string{"deploy", "qa", "--token=x"}).(*ArgumentError); !ok {
+		t.Error("expected choices error")
+	}
+
+	legacy, _ := root.Find("legacy")
+	if !legacy.IsDeprecated() || !legacy.IsHidden() {
+		t.Error("legacy should be deprecated and hidden")
+	}
+}
+
+// TestLoadSpecErrors tests reporting spec problems
+func TestLoadSpecErrors(t *testing.T) {
+	spec := `{
+	  "name": "app",
+	  "commands": [
+	    {"name": "a", "handler": "missing"},
+	    {"name": "b", "flags": [{"name": "n", "type": "complex"}]},
+	    {"name": "c", "aliases": ["a"]},
+	    {"name": "d", "handler": "bad"}
+	  ]
+	}`
+	handlers := map[string]interface{}{"bad": "not a function"}
+
+	_, err := LoadSpec([]byte(spec), handlers)
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	var specErr *SpecError
+	if !errors.As(err, &specErr) {
+		t.Fatalf("expected SpecError, got %T: %v", err, err)
+	}
+	for _, want := range []string{`handler "missing" is not registered`, `unsupported type "complex"`, "'a' is already used"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in:\n%v", want, err)
+		}
+	}
+
+	// Definition problems are found once the tree is complete
+	_, err = LoadSpec([]byte(`{"name": "app", "handler": "bad"}`), handlers)
+	var defErr *DefinitionError
+	if !errors.As(err, &defErr) {
+		t.Errorf("expected DefinitionError for a bad handler, got %v", err)
+	}
+
+	if _, err := LoadSpec([]byte(`{"name": "app", "unknown": 1}`), nil); err == nil {
+		t.Error("expected error for unknown spec fields")
+	}
+}
+
+// TestSpecRoundTrip tests that an exported spec loads back to the same tree
+func TestSpecRoundTrip(t *testing.T) {
+	handlers := map[string]interface{}{
+		"deploy": func(ctx context.Context, cmd *Command, env string, replicas int) error { return nil },
+	}
+	root, err := LoadSpec([]byte(testSpec), handlers)
+	if err != nil {
+		t.Fatalf("LoadSpec failed: %v", err)
+	}
+	AddCompletion(root)
+
+	exported, err := root.ExportSpec()
+	if err != nil {
+		t.Fatalf("ExportSpec failed: %v", err)
+	}
+	if strings.Contains(string(exported), "__") {
+		t.Errorf("internal commands should not be exported:\n%s", exported)
+	}
+
+	path := filepath.Join(t.TempDir(), "spec.json")
+	if err := os.WriteFile(path, exported, 0644); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadSpecFile(path, handlers)
+	if err != nil {
+		t.Fatalf("reloading exported spec failed: %v\n%s", err, exported)
+	}
+	again, err := reloaded.ExportSpec()
+	if err != nil {
+		t.Fatalf("ExportSpec failed: %v", err)
+	}
+	if string(again) != string(exported) {
+		t.Errorf("round trip changed the spec:\n%s\n---\n%s", exported, again)
+	}
+}
+
+// TestSpecRoundTripBuiltins tests that the version and shell commands are
+// left out of the spec and added again on the loaded tree
+func TestSpecRoundTripBuiltins(t *testing.T) {
+	exported, err := Root("app").Version("1.0").Shell().ExportSpec()
+	if err != nil {
+		t.Fatalf("ExportSpec failed: %v", err)
+	}
+	if strings.Contains(string(exported), "version") || strings.Contains(string(exported), "shell") {
+		t.Errorf("built-in commands should not be exported:\n%s", exported)
+	}
+
+	root, err := LoadSpec(exported, nil)
+	if err != nil {
+		t.Fatalf("LoadSpec failed: %v", err)
+	}
+	root.Version("2.0").Shell()
+	out := captureStdout(t, func() {
+		if err := root.ExecuteWithArgs([]string{"version"}); err != nil {
+			t.Errorf("version failed: %v", err)
+		}
+	})
+	if out != "app 2.0\n" {
+		t.Errorf("expected the built-in version command, got %q", out)
+	}
+	if shell := root.GetCommands()["shell"]; shell == nil || !shell.builtin {
+		t.Error("expected the built-in shell command")
+	}
+}
+
+// specLevel is a flag type with its own Set method
+type specLevel int
+
+func (l *specLevel) Set(value string) error {
+	*l = specLevel(len(value))
+	return nil
+}
+
+// TestExportSpecUnsupportedFlag tests exporting flags the spec cannot describe
+func TestExportSpecUnsupportedFlag(t *testing.T) {
+	var ports []int
+	var config ExistingFile
+	var input Input
+	var level specLevel
+	var size int64
+
+	tests := []struct {
+		name string
+		root *Command
+	}{
+		{"int slice", Root("app").Flag(&ports, "port", "", []int{}, "Ports")},
+		{"path type", Root("app").Flag(&config, "config", "", nil, "Config file")},
+		{"input", Root("app").Flag(&input, "input", "", nil, "Input")},
+		{"Set-based type", Root("app").Flag(&level, "level", "", nil, "Level")},
+		{"sized int", Root("app").Flag(&size, "size", "", nil, "Size")},
+	}
+	for _, tt := range tests {
+		_, err := tt.root.ExportSpec()
+		var specErr *SpecError
+		if !errors.As(err, &specErr) || !strings.Contains(err.Error(), "unsupported type") {
+			t.Errorf("%s: expected SpecError for unsupported type, got %v", tt.name, err)
+		}
+	}
+}
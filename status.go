@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Deprecated marks the command as deprecated. It keeps working but is hidden
+// from help and completion, and msg is printed to stderr whenever it is used,
+// e.g. Deprecated("use 'deploy' instead").
+func (c *Command) Deprecated(msg string) *Command {
+	c.deprecated = msg
+	return c
+}
+
+// IsDeprecated returns whether the command is deprecated
+func (c *Command) IsDeprecated() bool {
+	return c.deprecated != ""
+}
+
+// GetDeprecated returns the deprecation message, or "" if not deprecated
+func (c *Command) GetDeprecated() string {
+	return c.deprecated
+}
+
+// Experimental marks the command as experimental, which is shown in help
+func (c *Command) Experimental() *Command {
+	c.experimental = true
+	return c
+}
+
+// IsExperimental returns whether the command is experimental
+func (c *Command) IsExperimental() bool {
+	return c.experimental
+}
+
+// GateEnv makes the command behave as nonexistent unless the environment
+// variable name is set to a true value (1, t, true, ...)
+func (c *Command) GateEnv(name string) *Command {
+	c.gateEnv = name
+	return c
+}
+
+// GateFlag makes the command behave as nonexistent unless the boolean flag
+// name, defined on the command's parent or an ancestor, is passed
+func (c *Command) GateFlag(name string) *Command {
+	c.gateFlag = name
+	return c
+}
+
+// SetStderr sets the writer for diagnostics such as deprecation notices for
+// this command and its subcommands (default: os.Stderr)
+func (c *Command) SetStderr(w io.Writer) *Command {
+	c.stderr = w
+	return c
+}
+
+// stderrWriter returns the nearest stderr set on this command or an ancestor
+func (c *Command) stderrWriter() io.Writer {
+	for current := c; current != nil; current = current.parent {
+		if current.stderr != nil {
+			return current.stderr
+		}
+	}
+	return os.Stderr
+}
+
+// warnDeprecated prints the deprecation notice if the command is deprecated
+func (c *Command) warnDeprecated() {
+	if c.deprecated != "" {
+		fmt.Fprintf(c.stderrWriter(), "Command %q is deprecated, %s\n", c.getCommandPath(), c.deprecated)
+	}
+}
+
+// isListed reports whether the command is shown in help, completion and
+// suggestions: it is neither hidden, deprecated nor behind a closed gate
+func (c *Command) isListed() bool {
+	return !c.IsHidden() && !c.IsDeprecated() && c.gateOpen(nil)
+}
+
+// hasListedSubcommands reports whether any subcommand is listed in help
+func (c *Command) hasListedSubcommands() bool {
+	for _, cmd := range c.subcommands {
+		if cmd.isListed() {
+			return true
+		}
+	}
+	return false
+}
+
+// gateOpen reports whether the command's gate, if any, is open. args are
+// the arguments being executed, searched for the gate flag.
+func (c *Command) gateOpen(args []string) bool {
+	if c.gateEnv == "" && c.gateFlag == "" {
+		return true
+	}
+	if c.gateEnv != "" {
+		if enabled, err := strconv.ParseBool(os.Getenv(c.gateEnv)); err == nil && enabled {
+			return true
+		}
+	}
+	if c.gateFlag != "" && c.parent != nil {
+		flag := c.parent.inheritedFlags().GetFlag(c.gateFlag)
+		if flag == nil || flag.flagType.Kind() != reflect.Bool {
+			return false
+		}
+		if flag.IsSet() && flag.value.Bool() {
+			return true
+		}
+		for _, arg := range args {
+			if arg == "--" {
+				break
+			}
+			name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			if !strings.HasPrefix(arg, "-") || !flag.HasName(name) {
+				continue
+			}
+			if !hasValue {
+				return true
+			}
+			if enabled, err := strconv.ParseBool(value); err == nil && enabled {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// gateHint describes how to open the command's gate
+func (c *Command) gateHint() string {
+	var ways []string
+	if c.gateEnv != "" {
+		ways = append(ways, fmt.Sprintf("set %s=1", c.gateEnv))
+	}
+	if c.gateFlag != "" {
+		ways = append(ways, fmt.Sprintf("pass --%s", c.gateFlag))
+	}
+	return strings.Join(ways, " or ") + " to enable it"
+}
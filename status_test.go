@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestDeprecatedCommand tests the deprecation notice and hiding from listings
+func TestDeprecatedCommand(t *testing.T) {
+	var stderr bytes.Buffer
+	ran := false
+	root := Root("app").SetStderr(&stderr)
+	deploy := Cmd("deploy")
+	legacy := Cmd("legacy").
+		Deprecated("use 'deploy' instead").
+		Action(func(ctx context.Context, c *Command) error {
+			ran = true
+			return nil
+		})
+	deploy.AddCommand(legacy)
+	root.AddCommand(deploy)
+
+	if err := root.ExecuteWithArgs([]string{"deploy", "legacy"}); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if !ran {
+		t.Error("deprecated command should still run")
+	}
+	if got := stderr.String(); got != "Command \"app deploy legacy\" is deprecated, use 'deploy' instead\n" {
+		t.Errorf("unexpected deprecation notice: %q", got)
+	}
+	if !legacy.IsDeprecated() || legacy.GetDeprecated() != "use 'deploy' instead" {
+		t.Error("deprecation should be recorded")
+	}
+
+	if out := captureStdout(t, deploy.ShowHelp); strings.Contains(out, "legacy") {
+		t.Errorf("deprecated command should be hidden from help:\n%s", out)
+	}
+	if containsWord(getCompletionWords(deploy, nil), "legacy") {
+		t.Error("deprecated command should not be completed")
+	}
+}
+
+// TestExperimentalCommand tests the experimental marker in help
+func TestExperimentalCommand(t *testing.T) {
+	root := Root("app")
+	beta := Cmd("beta").Description("Try new things").Experimental()
+	root.AddCommand(beta)
+
+	if !beta.IsExperimental() {
+		t.Error("command should be experimental")
+	}
+	if out := captureStdout(t, root.ShowHelp); !strings.Contains(out, "(experimental)") {
+		t.Errorf("expected experimental marker in help:\n%s", out)
+	}
+	if out := captureStdout(t, beta.ShowHelp); !strings.Contains(out, "experimental") {
+		t.Errorf("expected experimental notice in command help:\n%s", out)
+	}
+}
+
+// TestGatedCommandEnv tests a command gated by an environment variable
+func TestGatedCommandEnv(t *testing.T) {
+	ran := false
+	root := Root("app")
+	root.AddCommand(Cmd("beta").
+		GateEnv("APP_BETA").
+		Action(func(ctx context.Context, c *Command) error {
+			ran = true
+			return nil
+		}))
+
+	t.Setenv("APP_BETA", "")
+	err := root.ExecuteWithArgs([]string{"beta"})
+	notFound, ok := err.(*CommandNotFoundError)
+	if !ok {
+		t.Fatalf("expected CommandNotFoundError, got %T: %v", err, err)
+	}
+	if notFound.Hint != "set APP_BETA=1 to enable it" {
+		t.Errorf("unexpected hint: %q", notFound.Hint)
+	}
+	if containsWord(getCompletionWords(root, nil), "beta") {
+		t.Error("gated command should not be completed")
+	}
+
+	t.Setenv("APP_BETA", "1")
+	if err := root.ExecuteWithArgs([]string{"beta"}); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if !ran {
+		t.Error("gated command should run once enabled")
+	}
+	if !containsWord(getCompletionWords(root, nil), "beta") {
+		t.Error("enabled command should be completed")
+	}
+}
+
+// TestGatedCommandFlag tests a command gated by a root flag
+func TestGatedCommandFlag(t *testing.T) {
+	var enableBeta bool
+	ran := false
+	root := Root("app").Flag(&enableBeta, "enable-beta", "", false, "Enable beta commands")
+	root.AddCommand(Cmd("beta").
+		GateFlag("enable-beta").
+		Action(func(ctx context.Context, c *Command) error {
+			ran = true
+			return nil
+		}))
+
+	err := root.ExecuteWithArgs([]string{"beta"})
+	if notFound, ok := err.(*CommandNotFoundError); !ok || !strings.Contains(notFound.Error(), "pass --enable-beta") {
+		t.Fatalf("expected gated CommandNotFoundError, got %v", err)
+	}
+
+	for _, args := range [][]string{{"--enable-beta", "beta"}, {"beta", "--enable-beta=true"}} {
+		ran = false
+		if err := root.ExecuteWithArgs(args); err != nil {
+			t.Fatalf("%v: execution failed: %v", args, err)
+		}
+		if !ran {
+			t.Errorf("%v: gated command should run", args)
+		}
+	}
+}
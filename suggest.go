@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// defaultSuggestionDistance is the edit distance used when none is configured
+const defaultSuggestionDistance = 2
+
+// PrintError writes err to stderr in the default format, followed by any
+// suggestions carried by the error
+func PrintError(err error) {
+	FprintError(os.Stderr, err)
+}
+
+// FprintError writes err to w in the default format, followed by any
+// suggestions carried by the error
+func FprintError(w io.Writer, err error) {
+	if err == nil {
+		return
+	}
+	fmt.Fprintf(w, "Error: %v\n", err)
+
+	// Suggestions are found through wrapped and joined errors too
+	var suggestions []string
+	var notFound *CommandNotFoundError
+	var flagErr *FlagError
+	switch {
+	case errors.As(err, &notFound):
+		suggestions = notFound.Suggestions
+	case errors.As(err, &flagErr):
+		suggestions = flagErr.Suggestions
+	}
+	if len(suggestions) > 0 {
+		fmt.Fprintf(w, "\nDid you mean this?\n")
+		for _, suggestion := range suggestions {
+			fmt.Fprintf(w, "\t%s\n", suggestion)
+		}
+	}
+}
+
+// commandNotFound builds the error for an unknown subcommand of c
+func (c *Command) commandNotFound(name string) error {
+	return &CommandNotFoundError{
+		Name:        name,
+		Cmd:         c,
+		Suggestions: c.suggestCommands(name),
+	}
+}
+
+// suggestCommands returns the names of visible subcommands whose name or an
+// alias is close to name
+func (c *Command) suggestCommands(name string) []string {
+	if c.root().suggestionsDisabled {
+		return nil
+	}
+
+	distance := c.root().maxSuggestionDistance()
+	best := make(map[string]int)
+	for key, cmd := range c.subcommands {
+		if !cmd.isListed() {
+			continue
+		}
+		for _, candidate := range append([]string{key}, cmd.aliases...) {
+			if d, ok := suggestionScore(name, candidate, distance); ok {
+				if prev, seen := best[key]; !seen || d < prev {
+					best[key] = d
+				}
+			}
+		}
+	}
+	if c.hasHelpCommand() {
+		if d, ok := suggestionScore(name, c.helpFlag, distance); ok {
+			best[c.helpFlag] = d
+		}
+	}
+	return rankSuggestions(best)
+}
+
+// suggestFlags returns the visible flags, as "--name", whose names are close
+// to name. Single-character names are too short to suggest meaningfully.
+func (c *Command) suggestFlags(name string) []string {
+	if c.root().suggestionsDisabled || len(name) < 2 {
+		return nil
+	}
+
+	distance := c.root().maxSuggestionDistance()
+	best := make(map[string]int)
+	for _, flag := range c.inheritedFlags().GetFlags() {
+		if flag.IsHidden() {
+			continue
+		}
+		display := "--" + flag.PrimaryName()
+		for _, candidate := range flag.names {
+			if len(candidate) < 2 {
+				continue
+			}
+			if d, ok := suggestionScore(name, candidate, distance); ok {
+				if prev, seen := best[display]; !seen || d < prev {
+					best[display] = d
+				}
+			}
+		}
+	}
+	return rankSuggestions(best)
+}
+
+// maxSuggestionDistance returns the configured distance or the default
+func (c *Command) maxSuggestionDistance() int {
+	if c.suggestionDistance > 0 {
+		return c.suggestionDistance
+	}
+	return defaultSuggestionDistance
+}
+
+// suggestionScore reports whether candidate should be suggested for name: it
+// starts with name or is within maxDistance edits. Prefix matches score 0.
+func suggestionScore(name, candidate string, maxDistance int) (int, bool) {
+	lowerName := strings.ToLower(name)
+	lowerCandidate := strings.ToLower(candidate)
+	if strings.HasPrefix(lowerCandidate, lowerName) {
+		return 0, true
+	}
+	d := levenshtein(lowerName, lowerCandidate)
+	return d, d <= maxDistance
+}
+
+// rankSuggestions orders suggestions by score, then alphabetically
+func rankSuggestions(scores map[string]int) []string {
+	if len(scores) == 0 {
+		return nil
+	}
+	suggestions := make([]string, 0, len(scores))
+	for suggestion := range scores {
+		suggestions = append(suggestions, suggestion)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if scores[suggestions[i]] != scores[suggestions[j]] {
+			return scores[suggestions[i]] < scores[suggestions[j]]
+		}
+		return suggestions[i] < suggestions[j]
+	})
+	return suggestions
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestLevenshtein tests the edit distance helper
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"deploy", "deploy", 0},
+		{"deply", "deploy", 1},
+		{"dpeloy", "deploy", 2},
+		{"", "abc", 3},
+		{"status", "stats", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestCommandSuggestions tests suggestions for unknown subcommands
+func TestCommandSuggestions(t *testing.T) {
+	root := Root("app")
+	root.AddCommand(Cmd("deploy"))
+	root.AddCommand(Cmd("delete").Aliases("remove"))
+	root.AddCommand(Cmd("status"))
+	root.AddCommand(Cmd("deplay").Hidden())
+
+	err := root.ExecuteWithArgs([]string{"deplyo"})
+	notFound, ok := err.(*CommandNotFoundError)
+	if !ok {
+		t.Fatalf("expected CommandNotFoundError, got %T: %v", err, err)
+	}
+	if len(notFound.Suggestions) != 1 || notFound.Suggestions[0] != "deploy" {
+		t.Errorf("expected [deploy], got %v", notFound.Suggestions)
+	}
+
+	// Aliases suggest the command they belong to
+	err = root.ExecuteWithArgs([]string{"remov"})
+	if notFound, ok := err.(*CommandNotFoundError); !ok || len(notFound.Suggestions) != 1 || notFound.Suggestions[0] != "delete" {
+		t.Errorf("expected [delete] via alias, got %v", err)
+	}
+
+	// Prefixes are suggested first
+	err = root.ExecuteWithArgs([]string{"de"})
+	if notFound, ok := err.(*CommandNotFoundError); !ok || len(notFound.Suggestions) != 2 || notFound.Suggestions[0] != "delete" {
+		t.Errorf("expected [delete deploy], got %v", err)
+	}
+
+	err = root.ExecuteWithArgs([]string{"xyz"})
+	if notFound, ok := err.(*CommandNotFoundError); !ok || len(notFound.Suggestions) != 0 {
+		t.Errorf("expected no suggestions, got %v", err)
+	}
+}
+
+// TestSuggestionSettings tests the per-root switch and distance threshold
+func TestSuggestionSettings(t *testing.T) {
+	root := Root("app")
+	root.AddCommand(Cmd("deploy"))
+
+	root.DisableSuggestions()
+	err := root.ExecuteWithArgs([]string{"deply"})
+	if notFound, ok := err.(*CommandNotFoundError); !ok || len(notFound.Suggestions) != 0 {
+		t.Errorf("expected no suggestions when disabled, got %v", err)
+	}
+
+	root.EnableSuggestions().SuggestionDistance(3)
+	err = root.ExecuteWithArgs([]string{"dexxxy"})
+	if notFound, ok := err.(*CommandNotFoundError); !ok || len(notFound.Suggestions) != 1 {
+		t.Errorf("expected a suggestion with distance 3, got %v", err)
+	}
+
+	root.SuggestionDistance(1)
+	err = root.ExecuteWithArgs([]string{"dexxxy"})
+	if notFound, ok := err.(*CommandNotFoundError); !ok || len(notFound.Suggestions) != 0 {
+		t.Errorf("expected no suggestions with distance 1, got %v", err)
+	}
+}
+
+// TestFlagSuggestions tests suggestions for unknown flags, including inherited ones
+func TestFlagSuggestions(t *testing.T) {
+	var verbose, secret bool
+	var output string
+	root := Root("app").
+		Flag(&verbose, "verbose", "v", false, "Verbose").
+		FlagHidden(&secret, "verbatim", "", false, "Hidden")
+	child := Cmd("get").Flag(&output, "output", "o", "", "Output")
+	root.AddCommand(child)
+
+	err := root.ExecuteWithArgs([]string{"get", "--verbos"})
+	flagErr, ok := err.(*FlagError)
+	if !ok {
+		t.Fatalf("expected FlagError, got %T: %v", err, err)
+	}
+	if flagErr.Flag != "verbos" {
+		t.Errorf("expected flag name verbos, got %q", flagErr.Flag)
+	}
+	if len(flagErr.Suggestions) != 1 || flagErr.Suggestions[0] != "--verbose" {
+		t.Errorf("expected [--verbose], got %v", flagErr.Suggestions)
+	}
+
+	err = root.ExecuteWithArgs([]string{"get", "--ouptut=json"})
+	if flagErr, ok := err.(*FlagError); !ok || len(flagErr.Suggestions) != 1 || flagErr.Suggestions[0] != "--output" {
+		t.Errorf("expected [--output], got %v", err)
+	}
+}
+
+// TestFprintError tests the default error printer
+func TestFprintError(t *testing.T) {
+	root := Root("app")
+	root.AddCommand(Cmd("deploy"))
+
+	var buf bytes.Buffer
+	FprintError(&buf, root.ExecuteWithArgs([]string{"deplo"}))
+	out := buf.String()
+	if !strings.HasPrefix(out, "Error: unknown command 'deplo' for 'app'") {
+		t.Errorf("unexpected error line: %q", out)
+	}
+	if !strings.Contains(out, "Did you mean this?\n\tdeploy\n") {
+		t.Errorf("expected suggestions in output, got %q", out)
+	}
+
+	buf.Reset()
+	FprintError(&buf, root.ExecuteWithArgs([]string{"xyz"}))
+	if strings.Contains(buf.String(), "Did you mean") {
+		t.Errorf("expected no suggestions block, got %q", buf.String())
+	}
+
+	// Suggestions survive wrapping and joining
+	err := root.ExecuteWithArgs([]string{"deplo"})
+	for _, wrapped := range []error{fmt.Errorf("running: %w", err), errors.Join(errors.New("other"), err)} {
+		buf.Reset()
+		FprintError(&buf, wrapped)
+		if !strings.Contains(buf.String(), "Did you mean this?\n\tdeploy\n") {
+			t.Errorf("expected suggestions for wrapped error, got %q", buf.String())
+		}
+	}
+}
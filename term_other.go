@@ -0,0 +1,14 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package cli
+
+import (
+	"errors"
+	"os"
+)
+
+// makeRaw is not supported on this platform: the shell reads lines in the
+// terminal's normal mode, without editing keys
+func makeRaw(f *os.File) (restore func(), err error) {
+	return nil, errors.New("raw terminal mode is not supported")
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SkipSubcommands can be returned by a Walk function to skip the
+// subcommands of the command being visited
+var SkipSubcommands = errors.New("skip subcommands")
+
+// Find resolves a command path below c by names or aliases, e.g.
+// Find("db", "backup", "create") or Find("db backup create").
+// With no path it returns c.
+func (c *Command) Find(path ...string) (*Command, error) {
+	current := c
+	for _, part := range path {
+		for _, name := range strings.Fields(part) {
+			next := current.lookupSubcommand(name)
+			if next == nil {
+				return nil, current.commandNotFound(name)
+			}
+			current = next.materialize()
+		}
+	}
+	return current, nil
+}
+
+// Walk calls fn for c and every command below it, parents before children
+// and siblings in the configured order. Lazy commands are built on the way.
+// Returning SkipSubcommands skips the children of the current command; any
+// other error stops the walk.
+func (c *Command) Walk(fn func(*Command) error) error {
+	if err := fn(c); err != nil {
+		if err == SkipSubcommands {
+			return nil
+		}
+		return err
+	}
+	for _, cmd := range c.sortedSubcommands() {
+		if err := cmd.materialize().Walk(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveCommand removes the named subcommands, e.g. to strip internal tools
+// from release builds. Removed commands are detached from c; unknown names
+// are ignored.
+func (c *Command) RemoveCommand(names ...string) *Command {
+	for _, name := range names {
+		cmd, exists := c.subcommands[name]
+		if !exists {
+			continue
+		}
+		c.detach(cmd)
+	}
+	return c
+}
+
+// ReplaceCommand swaps the subcommand with the same name as cmd for cmd,
+// keeping its position, or adds cmd if there is none. It panics if an alias
+// of cmd is used by another subcommand.
+func (c *Command) ReplaceCommand(cmd *Command) *Command {
+	old := c.subcommands[cmd.name]
+	if clash := c.nameClash(cmd, old); clash != "" {
+		panic(fmt.Sprintf("cannot replace command '%s' in '%s': %s", cmd.name, c.getCommandPath(), clash))
+	}
+	if old != nil && old != cmd {
+		old.parent = nil
+		old.invalidateFlags()
+	}
+	c.attach(cmd)
+	return c
+}
+
+// nameClash describes how the name or aliases of cmd collide with the
+// subcommands of c other than cmd itself and except, or returns ""
+func (c *Command) nameClash(cmd, except *Command) string {
+	names := append([]string{cmd.name}, cmd.aliases...)
+	for _, other := range c.sortedSubcommands() {
+		if other == cmd || other == except {
+			continue
+		}
+		for _, name := range names {
+			if other.hasName(name) {
+				return fmt.Sprintf("'%s' is already used by '%s'", name, other.getCommandPath())
+			}
+		}
+	}
+	return ""
+}
+
+// attach makes cmd a subcommand of c, detaching it from a previous parent
+func (c *Command) attach(cmd *Command) {
+	if cmd.parent != nil && cmd.parent != c && cmd.parent.subcommands[cmd.name] == cmd {
+		cmd.parent.detach(cmd)
+	}
+	cmd.parent = c
+	if _, exists := c.subcommands[cmd.name]; !exists {
+		c.order = append(c.order, cmd.name)
+	}
+	c.subcommands[cmd.name] = cmd
+	cmd.invalidateFlags()
+}
+
+// detach removes cmd from the subcommands of c
+func (c *Command) detach(cmd *Command) {
+	delete(c.subcommands, cmd.name)
+	for i, name := range c.order {
+		if name == cmd.name {
+			c.order = append(c.order[:i:i], c.order[i+1:]...)
+			break
+		}
+	}
+	cmd.parent = nil
+	cmd.invalidateFlags()
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testTree() *Command {
+	root := Root("app")
+	db := Cmd("db").Aliases("database")
+	backup := Cmd("backup")
+	backup.AddCommand(Cmd("create"))
+	backup.AddCommand(Cmd("restore"))
+	db.AddCommand(backup)
+	root.AddCommand(db)
+	root.AddCommand(Cmd("debug"))
+	return root
+}
+
+// TestFind tests resolving command paths
+func TestFind(t *testing.T) {
+	root := testTree()
+
+	for _, path := range [][]string{{"db", "backup", "create"}, {"db backup create"}, {"database", "backup create"}} {
+		cmd, err := root.Find(path...)
+		if err != nil {
+			t.Fatalf("%v: Find failed: %v", path, err)
+		}
+		if cmd.getCommandPath() != "app db backup create" {
+			t.Errorf("%v: found %s", path, cmd.getCommandPath())
+		}
+	}
+
+	if cmd, err := root.Find(); err != nil || cmd != root {
+		t.Error("Find without a path should return the command itself")
+	}
+
+	_, err := root.Find("db", "backpu")
+	notFound, ok := err.(*CommandNotFoundError)
+	if !ok || notFound.Cmd.GetName() != "db" || notFound.Name != "backpu" {
+		t.Errorf("expected CommandNotFoundError under db, got %v", err)
+	}
+}
+
+// TestWalk tests visiting every command in order
+func TestWalk(t *testing.T) {
+	root := testTree()
+
+	var visited []string
+	err := root.Walk(func(cmd *Command) error {
+		visited = append(visited, cmd.GetName())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk failed: %v", err)
+	}
+	if got := strings.Join(visited, ","); got != "app,db,backup,create,restore,debug" {
+		t.Errorf("unexpected walk order: %s", got)
+	}
+
+	visited = nil
+	root.Walk(func(cmd *Command) error {
+		visited = append(visited, cmd.GetName())
+		if cmd.GetName() == "backup" {
+			return SkipSubcommands
+		}
+		return nil
+	})
+	if got := strings.Join(visited, ","); got != "app,db,backup,debug" {
+		t.Errorf("unexpected walk with skip: %s", got)
+	}
+
+	stop := errors.New("stop")
+	if err := root.Walk(func(cmd *Command) error { return stop }); err != stop {
+		t.Errorf("expected walk to return the callback error, got %v", err)
+	}
+}
+
+// TestRemoveCommand tests removing subcommands
+func TestRemoveCommand(t *testing.T) {
+	root := testTree()
+	debug, _ := root.Find("debug")
+
+	root.RemoveCommand("debug", "missing")
+	if _, err := root.Find("debug"); err == nil {
+		t.Error("debug should be removed")
+	}
+	if debug.GetParent() != nil {
+		t.Error("removed command should be detached")
+	}
+	if err := root.ExecuteWithArgs([]string{"debug"}); err == nil {
+		t.Error("removed command should not execute")
+	}
+
+	// The name can be reused after removal
+	root.AddCommand(Cmd("debug"))
+}
+
+// TestReplaceCommand tests replacing subcommands in place
+func TestReplaceCommand(t *testing.T) {
+	root := Root("app").OrderCommands(OrderDeclaration)
+	old := Cmd("build")
+	root.AddCommand(old)
+	root.AddCommand(Cmd("test"))
+
+	ran := false
+	replacement := Cmd("build").Action(func(ctx interface{}, c *Command) error {
+		ran = true
+		return nil
+	})
+	root.ReplaceCommand(replacement)
+
+	if old.GetParent() != nil || replacement.GetParent() != root {
+		t.Error("parent pointers should follow the replacement")
+	}
+	if cmds := root.sortedSubcommands(); cmds[0] != replacement {
+		t.Error("replacement should keep the original position")
+	}
+	if err := root.ExecuteWithArgs([]string{"build"}); err != nil || !ran {
+		t.Errorf("replacement should run, got %v", err)
+	}
+
+	// Replacing a missing command adds it
+	root.ReplaceCommand(Cmd("lint"))
+	if _, err := root.Find("lint"); err != nil {
+		t.Error("ReplaceCommand should add a missing command")
+	}
+}
+
+// TestAddCommandCollisions tests that name and alias collisions are rejected
+func TestAddCommandCollisions(t *testing.T) {
+	expectPanic := func(name string, fn func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s: expected panic", name)
+			}
+		}()
+		fn()
+	}
+
+	root := Root("app")
+	root.AddCommand(Cmd("remove").Aliases("rm"))
+
+	expectPanic("same name", func() { root.AddCommand(Cmd("remove")) })
+	expectPanic("name matches alias", func() { root.AddCommand(Cmd("rm")) })
+	expectPanic("alias matches name", func() { root.AddCommand(Cmd("delete").Aliases("remove")) })
+	expectPanic("replace with clashing alias", func() { root.ReplaceCommand(Cmd("list").Aliases("rm")) })
+
+	// Moving a command keeps parent pointers consistent
+	other := Root("other")
+	cmd, _ := root.Find("remove")
+	other.AddCommand(cmd)
+	if cmd.GetParent() != other {
+		t.Error("moved command should point to its new parent")
+	}
+	if _, err := root.Find("remove"); err == nil {
+		t.Error("moved command should be removed from its old parent")
+	}
+}
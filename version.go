@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"text/template"
+
+	"github.com/nyxstack/color"
+)
+
+// Build information overrides, set with the linker, e.g.
+// -ldflags "-X github.com/nyxstack/cli.buildVersion=v1.2.3"
+var (
+	buildVersion  string
+	buildRevision string
+	buildDirty    string // "true" or "false"
+	buildTime     string
+)
+
+// readBuildInfo is replaced in tests
+var readBuildInfo = debug.ReadBuildInfo
+
+// DefaultVersionTemplate is the template used to print version information
+const DefaultVersionTemplate = `{{.Name}} {{.Version}}
+{{- if .Revision}} ({{.Revision}}{{if .Dirty}}, dirty{{end}}){{end}}
+{{- if .BuildTime}} built {{.BuildTime}}{{end}}
+`
+
+// VersionInfo describes the running build, as printed by the version
+// command and flag
+type VersionInfo struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	Dirty     bool   `json:"dirty"`
+	BuildTime string `json:"buildTime,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Version registers a "version" subcommand and a --version flag on the root,
+// unless the root already has a version subcommand or flag.
+// Information comes from runtime/debug.ReadBuildInfo, overridden by ldflags
+// (buildVersion, buildRevision, buildDirty, buildTime) and, for the version,
+// by a non-empty version argument.
+func (c *Command) Version(version string) *Command {
+	c.version = version
+	if c.versionEnabled {
+		return c
+	}
+	c.versionEnabled = true
+
+	// A user-defined version command takes precedence
+	if existing, _ := c.Find("version"); existing != nil {
+		return c
+	}
+
+	var output string
+	versionCmd := Cmd("version").
+		Description("Show version information").
+		Flag(&output, "output", "o", "text", "Output format (text or json)").
+		Action(func(ctx context.Context, cmd *Command) error {
+			return cmd.printVersion(output)
+		})
+	versionCmd.builtin = true
+	c.AddCommand(versionCmd)
+	return c
+}
+
+// VersionTemplate sets the text/template used to print version information,
+// executed with a VersionInfo (default: DefaultVersionTemplate)
+func (c *Command) VersionTemplate(tmpl string) *Command {
+	c.versionTemplate = tmpl
+	return c
+}
+
+// GetVersionInfo returns the version information of the running build
+func (c *Command) GetVersionInfo() VersionInfo {
+	info := VersionInfo{
+		Name:      c.root().name,
+		Version:   "(devel)",
+		GoVersion: runtime.Version(),
+	}
+
+	if build, ok := readBuildInfo(); ok {
+		if build.Main.Version != "" {
+			info.Version = build.Main.Version
+		}
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Revision = setting.Value
+			case "vcs.modified":
+				info.Dirty = setting.Value == "true"
+			case "vcs.time":
+				info.BuildTime = setting.Value
+			}
+		}
+		if build.GoVersion != "" {
+			info.GoVersion = build.GoVersion
+		}
+	}
+
+	if buildVersion != "" {
+		info.Version = buildVersion
+	}
+	if buildRevision != "" {
+		info.Revision = buildRevision
+	}
+	if dirty, err := strconv.ParseBool(buildDirty); err == nil {
+		info.Dirty = dirty
+	}
+	if buildTime != "" {
+		info.BuildTime = buildTime
+	}
+	if version := c.root().version; version != "" {
+		info.Version = version
+	}
+	return info
+}
+
+// hasVersionFlag reports whether the --version flag is available on c. A
+// flag named "version" defined on the root takes precedence.
+func (c *Command) hasVersionFlag() bool {
+	return c.parent == nil && c.versionEnabled && c.flags.GetFlag("version") == nil
+}
+
+// showVersionFlag lists the --version flag in help
+func (c *Command) showVersionFlag() {
+	if c.hasVersionFlag() {
+		fmt.Fprintf(c.stdoutWriter(), "  %-30s %s\n", color.Green+"--version"+color.Reset, "Show version information")
+	}
+}
+
+// printVersion writes the version information as text or json
+func (c *Command) printVersion(output string) error {
+	info := c.GetVersionInfo()
+
+	switch output {
+	case "json":
+		encoder := json.NewEncoder(c.stdoutWriter())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(info)
+	case "text", "":
+		text := c.root().versionTemplate
+		if text == "" {
+			text = DefaultVersionTemplate
+		}
+		tmpl, err := template.New("version").Parse(text)
+		if err != nil {
+			return fmt.Errorf("invalid version template: %w", err)
+		}
+		return tmpl.Execute(c.stdoutWriter(), info)
+	default:
+		return &FlagError{
+			Flag: "output",
+			Msg:  fmt.Sprintf("unknown output format %q, must be text or json", output),
+			Cmd:  c,
+		}
+	}
+}
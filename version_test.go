@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+// stubBuildInfo replaces the build information for the duration of a test
+func stubBuildInfo(t *testing.T, info *debug.BuildInfo) {
+	t.Helper()
+	original := readBuildInfo
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return info, info != nil }
+	t.Cleanup(func() { readBuildInfo = original })
+}
+
+func testBuildInfo() *debug.BuildInfo {
+	return &debug.BuildInfo{
+		GoVersion: "go1.24.2",
+		Main:      debug.Module{Path: "example.com/app", Version: "v1.4.0"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "abc123"},
+			{Key: "vcs.modified", Value: "true"},
+			{Key: "vcs.time", Value: "2026-01-02T03:04:05Z"},
+		},
+	}
+}
+
+// TestVersionFromBuildInfo tests reading version information from the build
+func TestVersionFromBuildInfo(t *testing.T) {
+	stubBuildInfo(t, testBuildInfo())
+	root := Root("app").Version("")
+
+	info := root.GetVersionInfo()
+	if info.Version != "v1.4.0" || info.Revision != "abc123" || !info.Dirty || info.BuildTime != "2026-01-02T03:04:05Z" || info.GoVersion != "go1.24.2" {
+		t.Errorf("unexpected version info: %+v", info)
+	}
+
+	out := captureStdout(t, func() {
+		if err := root.ExecuteWithArgs([]string{"--version"}); err != nil {
+			t.Errorf("--version failed: %v", err)
+		}
+	})
+	if out != "app v1.4.0 (abc123, dirty) built 2026-01-02T03:04:05Z\n" {
+		t.Errorf("unexpected --version output: %q", out)
+	}
+
+	cmdOut := captureStdout(t, func() {
+		if err := root.ExecuteWithArgs([]string{"version"}); err != nil {
+			t.Errorf("version command failed: %v", err)
+		}
+	})
+	if cmdOut != out {
+		t.Errorf("version command and flag should match: %q vs %q", cmdOut, out)
+	}
+}
+
+// TestVersionOverrides tests ldflags and explicit version overrides
+func TestVersionOverrides(t *testing.T) {
+	stubBuildInfo(t, testBuildInfo())
+	buildRevision, buildDirty = "def456", "false"
+	t.Cleanup(func() { buildRevision, buildDirty = "", "" })
+
+	info := Root("app").Version("v2.0.0").GetVersionInfo()
+	if info.Version != "v2.0.0" || info.Revision != "def456" || info.Dirty {
+		t.Errorf("unexpected version info: %+v", info)
+	}
+
+	stubBuildInfo(t, nil)
+	if info := Root("app").Version("").GetVersionInfo(); info.Version != "(devel)" {
+		t.Errorf("expected (devel) without build info, got %q", info.Version)
+	}
+}
+
+// TestVersionJSONAndTemplate tests the json output and custom templates
+func TestVersionJSONAndTemplate(t *testing.T) {
+	stubBuildInfo(t, testBuildInfo())
+	root := Root("app").Version("")
+
+	out := captureStdout(t, func() {
+		if err := root.ExecuteWithArgs([]string{"version", "--output=json"}); err != nil {
+			t.Errorf("version --output=json failed: %v", err)
+		}
+	})
+	var info VersionInfo
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		t.Fatalf("invalid json %q: %v", out, err)
+	}
+	if info.Name != "app" || info.Version != "v1.4.0" {
+		t.Errorf("unexpected json info: %+v", info)
+	}
+
+	if _, ok := root.ExecuteWithArgs([]string{"version", "--output=xml"}).(*FlagError); !ok {
+		t.Error("expected FlagError for unknown output format")
+	}
+
+	root.VersionTemplate("{{.Version}}\n")
+	out = captureStdout(t, func() { root.ExecuteWithArgs([]string{"--version"}) })
+	if out != "v1.4.0\n" {
+		t.Errorf("unexpected templated output: %q", out)
+	}
+}
+
+// TestVersionInHelp tests that the version flag and command are listed in help
+func TestVersionInHelp(t *testing.T) {
+	root := Root("app").Version("v1.0.0")
+	out := captureStdout(t, root.ShowHelp)
+	if !strings.Contains(out, "--version") || !strings.Contains(out, "Show version information") {
+		t.Errorf("expected version in help:\n%s", out)
+	}
+
+	// A user-defined version flag takes precedence
+	var version string
+	user := Root("app").Version("v1.0.0").Flag(&version, "version", "", "", "Target version")
+	if err := user.ExecuteWithArgs([]string{"--version=2"}); err != nil || version != "2" {
+		t.Errorf("user version flag should be parsed, got %q, %v", version, err)
+	}
+}
+
+// TestVersionFlagShadowedBySubcommand tests that a subcommand's own --version
+// flag is not taken for the root's
+func TestVersionFlagShadowedBySubcommand(t *testing.T) {
+	var target string
+	ran := false
+	root := Root("app").Version("1.0")
+	root.AddCommand(Cmd("release").
+		Flag(&target, "version", "", "", "Version to release").
+		Action(func(ctx context.Context, cmd *Command) error {
+			ran = true
+			return nil
+		}))
+
+	out := captureStdout(t, func() {
+		if err := root.ExecuteWithArgs([]string{"release", "--version=1.2"}); err != nil {
+			t.Errorf("release failed: %v", err)
+		}
+	})
+	if !ran || target != "1.2" || strings.Contains(out, "app 1.0") {
+		t.Errorf("release should run with its own --version, ran=%v target=%q output=%q", ran, target, out)
+	}
+
+	// A bool flag named version on a subcommand, without a value
+	var show bool
+	root.AddCommand(Cmd("plugin").Flag(&show, "version", "", false, "Show plugin version"))
+	out = captureStdout(t, func() { root.ExecuteWithArgs([]string{"plugin", "--version"}) })
+	if !show || strings.Contains(out, "app 1.0") {
+		t.Errorf("plugin --version should set the plugin flag, show=%v output=%q", show, out)
+	}
+
+	// Before the subcommand, --version is still the root's
+	out = captureStdout(t, func() { root.ExecuteWithArgs([]string{"--version", "release"}) })
+	if out != "app 1.0\n" {
+		t.Errorf("unexpected root --version output: %q", out)
+	}
+}
+
+// TestVersionWithRequiredFlags tests that the version command, like the flag,
+// runs without the root's required flags
+func TestVersionWithRequiredFlags(t *testing.T) {
+	var token string
+	root := Root("app").
+		FlagRequired(&token, "token", "", "", "API token").
+		Version("1.0")
+
+	for _, args := range [][]string{{"version"}, {"--version"}} {
+		out := captureStdout(t, func() {
+			if err := root.ExecuteWithArgs(args); err != nil {
+				t.Errorf("%v failed: %v", args, err)
+			}
+		})
+		if out != "app 1.0\n" {
+			t.Errorf("%v: unexpected output %q", args, out)
+		}
+	}
+}